@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func balanceCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "balance",
+		Action: balance,
+		Usage:  "print the flex-time balance",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "by",
+				Aliases: []string{"b"},
+				Value:   "day",
+				Usage:   "print the balance at the end of each `PERIOD`: day, week or month",
+			},
+			&cli.TimestampFlag{
+				Name:        "start",
+				Aliases:     []string{"s"},
+				Layout:      dateLayout,
+				Usage:       "first `DATE` to print, format 'd.M.'",
+				DefaultText: "first day of the month",
+			},
+			&cli.BoolFlag{
+				Name:  "today",
+				Usage: "include today, which counts the expected hours of the whole day",
+			},
+		},
+	}
+}
+
+// balancePeriod is the sum of the balance days of a period.
+type balancePeriod struct {
+	label            string
+	worked, expected float64
+	balance          float64
+}
+
+func balance(c *cli.Context) error {
+
+	periodKey, err := balancePeriodKey(c.String("by"))
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	if len(cfg.Balance.Start) == 0 {
+		return errors.New("set the start date of the balance as balance.start in the config")
+	}
+	balanceStart, yearly, err := parseConfigDate(cfg.Balance.Start)
+	if err != nil || yearly {
+		return fmt.Errorf("invalid balance.start %s, use format 'd.M.yyyy'", cfg.Balance.Start)
+	}
+
+	now := time.Now()
+	// Today is still in progress, so it would be short of the expected hours.
+	end := midnight(now).AddDate(0, 0, -1)
+	if c.Bool("today") {
+		end = midnight(now)
+	}
+	printStart := dateFlag(c, "start", now.AddDate(0, 0, 1-now.Day()))
+	if end.Before(balanceStart) {
+		return fmt.Errorf("balance starts at %s", balanceStart.Format("2.1.2006"))
+	}
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+	workCal, err := workCalendar(c, client, balanceStart, end)
+	if err != nil {
+		return err
+	}
+	rows, err := client.WorkLogsContext(c.Context, balanceStart, end)
+	if err != nil {
+		return errors.Wrap(err, "getting work logs")
+	}
+
+	days, err := hrflow.FlexBalance(rows, workCal, balanceStart, end, cfg.Balance.Opening, cfg.dailyHours())
+	if err != nil {
+		return err
+	}
+
+	var periods []*balancePeriod
+	for _, day := range days {
+		if day.Date.Before(printStart) {
+			continue
+		}
+		label := periodKey(day.Date)
+		if len(periods) == 0 || periods[len(periods)-1].label != label {
+			periods = append(periods, &balancePeriod{label: label})
+		}
+		period := periods[len(periods)-1]
+		period.worked += day.Worked
+		period.expected += day.Expected
+		period.balance = day.Balance
+	}
+
+	fmt.Printf("%-16s %7s %9s %8s %8s\n", "", "worked", "expected", "diff", "balance")
+	for _, period := range periods {
+		fmt.Printf("%-16s %7.2f %9.2f %+8.2f %+8.2f\n",
+			period.label, period.worked, period.expected, period.worked-period.expected, period.balance)
+	}
+
+	return nil
+}
+
+// balancePeriodKey returns a function that labels dates with the period they belong to.
+func balancePeriodKey(by string) (func(time.Time) string, error) {
+
+	switch by {
+	case "day":
+		return func(date time.Time) string {
+			return date.Format("Mon 02.01.2006")
+		}, nil
+	case "week":
+		return func(date time.Time) string {
+			year, week := date.ISOWeek()
+			return fmt.Sprintf("week %d/%d", week, year)
+		}, nil
+	case "month":
+		return func(date time.Time) string {
+			return date.Format("01/2006")
+		}, nil
+	}
+
+	return nil, fmt.Errorf("invalid period %s, use day, week or month", by)
+}
@@ -1,9 +1,14 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/myyra/hrflow/hrflow"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 )
@@ -18,7 +23,16 @@ func calendarCommandFactory() *cli.Command {
 			&cli.BoolFlag{
 				Name:    "all",
 				Aliases: []string{"a"},
-				Usage:   "print all dates instead of weekdays only",
+				Usage:   "print all dates instead of workdays and named days off only",
+			},
+			&cli.BoolFlag{
+				Name:    "refresh",
+				Aliases: []string{"r"},
+				Usage:   "fetch the days from HR Flow even if they are cached",
+			},
+			&cli.BoolFlag{
+				Name:  "offline",
+				Usage: "calculate the days from Finnish public holidays instead of fetching them from HR Flow",
 			},
 			&cli.IntFlag{
 				Name:    "count",
@@ -34,28 +48,26 @@ func calendar(c *cli.Context) error {
 
 	allDays := c.Bool("all")
 	count := c.Int("count")
-
-	client, err := clientFromConfig()
-	if err != nil {
-		return errors.Wrap(err, "creating client from config")
-	}
-	err = client.Authenticate()
-	if err != nil {
-		return errors.Wrap(err, "authentication failed")
-	}
+	var err error
 
 	today := time.Now()
 	end := time.Now().AddDate(0, 0, count)
 
-	days, err := client.Calendar(today, end)
+	var days []hrflow.CalendarDay
+	if c.Bool("offline") {
+		days, err = offlineCalendar(c, today, end)
+	} else {
+		days, err = onlineCalendar(c, today, end)
+	}
 	if err != nil {
 		return errors.Wrap(err, "getting calendar")
 	}
+
+	workCal := hrflow.NewWorkCalendar(days)
 	for _, day := range days {
-		if !allDays {
-			if day.Weekday == time.Saturday || day.Weekday == time.Sunday {
-				continue
-			}
+		// Named days off are printed so holidays falling on weekdays are visible.
+		if !allDays && !workCal.IsWorkday(day.Date) && len(strings.TrimSpace(day.Description)) == 0 {
+			continue
 		}
 		var dayType string
 		if day.Workday {
@@ -63,8 +75,104 @@ func calendar(c *cli.Context) error {
 		} else {
 			dayType = "holiday"
 		}
-		fmt.Println(day.Weekday, day.Date.Format("01.02.2006"), dayType)
+		fmt.Println(day.Weekday, day.Date.Format("01.02.2006"), dayType, strings.TrimSpace(day.Description))
+	}
+
+	return nil
+}
+
+// onlineCalendar returns the days of the selected employment from HR Flow, or the cache if possible.
+func onlineCalendar(c *cli.Context, start, end time.Time) ([]hrflow.CalendarDay, error) {
+
+	client, err := sessionClient(c)
+	if err != nil {
+		return nil, err
+	}
+	source, err := calendarSource(c.Context, client, c.Bool("refresh"))
+	if err != nil {
+		return nil, err
+	}
+
+	days, err := source.CalendarContext(c.Context, start, end)
+	if err != nil {
+		return nil, err
+	}
+	storeSession(client)
+
+	return days, nil
+}
+
+// offlineCalendar returns the days calculated from the Finnish public holidays and the days off in the config.
+func offlineCalendar(c *cli.Context, start, end time.Time) ([]hrflow.CalendarDay, error) {
+
+	days, err := daysOff()
+	if err != nil {
+		return nil, err
+	}
+	source := &hrflow.DaysOff{Source: hrflow.FinnishCalendar{}, Days: days}
+
+	return source.CalendarContext(c.Context, start, end)
+}
+
+// workCalendar returns the work calendar of the selected employment between start and end.
+func workCalendar(c *cli.Context, client *hrflow.Client, start, end time.Time) (*hrflow.WorkCalendar, error) {
+
+	source, err := calendarSource(c.Context, client, false)
+	if err != nil {
+		return nil, err
+	}
+	days, err := source.CalendarContext(c.Context, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting calendar")
+	}
+
+	return hrflow.NewWorkCalendar(days), nil
+}
+
+// calendarCacheTTL is how long cached calendar days are used. Holidays are known well in advance.
+const calendarCacheTTL = 30 * 24 * time.Hour
+
+// calendarSource returns the calendar of the selected employment, cached on disk unless recording or replaying,
+// with the days off in the config.
+func calendarSource(ctx context.Context, client *hrflow.Client, refresh bool) (hrflow.CalendarSource, error) {
+
+	days, err := daysOff()
+	if err != nil {
+		return nil, err
+	}
+	// Recordings need the calendar requests, and replayed days must not end up in the cache.
+	if cassetteTransport != nil {
+		return &hrflow.DaysOff{Source: client, Days: days}, nil
+	}
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	// The days are cached per employment, and without a stored session the employments aren't known before
+	// authenticating.
+	employment, err := client.Employment(time.Now())
+	if err != nil && len(client.Session().XSRFToken) == 0 {
+		err = client.AuthenticateContext(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "authentication failed")
+		}
+		employment, err = client.Employment(time.Now())
+	}
+	if err != nil {
+		// There is nothing to cache the days under without an employment.
+		return &hrflow.DaysOff{Source: client, Days: days}, nil
 	}
 
-	return err
+	// The days off are merged after the cache so changes to the config apply immediately.
+	return &hrflow.DaysOff{
+		Source: &hrflow.CalendarCache{
+			Source:  client,
+			Path:    filepath.Join(dir, "calendar.json"),
+			Key:     strconv.FormatInt(employment.EmploymentID, 10),
+			TTL:     calendarCacheTTL,
+			Refresh: refresh,
+		},
+		Days: days,
+	}, nil
 }
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/myyra/hrflow/hrflow/cassette"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// cassetteTransport replaces the transport of the client when recording or replaying requests.
+var cassetteTransport http.RoundTripper
+
+func setupCassette(c *cli.Context) error {
+
+	switch {
+	case c.IsSet("replay"):
+		replayer, err := cassette.Load(c.String("replay"))
+		if err != nil {
+			return errors.Wrap(err, "loading recording")
+		}
+		cassetteTransport = replayer
+	case c.IsSet("record"):
+		cassetteTransport = cassette.NewRecorder(nil)
+	}
+
+	return nil
+}
+
+func saveCassette(c *cli.Context) error {
+
+	recorder, ok := cassetteTransport.(*cassette.Recorder)
+	if !ok {
+		return nil
+	}
+
+	return errors.Wrap(recorder.Save(c.String("record")), "saving recording")
+}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/myyra/hrflow/hrflow"
 	"github.com/pkg/errors"
@@ -14,6 +16,107 @@ import (
 type config struct {
 	Username string `yaml:"username"`
 	Password string `yaml:"password"`
+	// Employment is the ID of the employment to report to, see the employments command.
+	Employment int64 `yaml:"employment"`
+	// BaseURL replaces the address of the service, e.g. for a test environment.
+	BaseURL string `yaml:"base_url"`
+	// LoginPath replaces the path of the login page.
+	LoginPath string `yaml:"login_path"`
+	// DaysOff are company specific days off that the calendar of HR Flow doesn't mark.
+	DaysOff []dayOffConfig `yaml:"days_off"`
+	// DailyHours is the length of a workday, 7.5 if not set.
+	DailyHours float64 `yaml:"daily_hours"`
+	// Balance is the starting point of the flex-time balance.
+	Balance balanceConfig `yaml:"balance"`
+	// Fill is the entry reported by the fill command.
+	Fill fillConfig `yaml:"fill"`
+}
+
+// defaultDailyHours is the length of a workday if the config doesn't set it.
+const defaultDailyHours = 7.5
+
+// dailyHours returns the length of a workday.
+func (c *config) dailyHours() float64 {
+
+	if c.DailyHours == 0 {
+		return defaultDailyHours
+	}
+
+	return c.DailyHours
+}
+
+// fillConfig is the default entry for days without reports. Dimensions are values or labels of list items.
+type fillConfig struct {
+	Start      string `yaml:"start"`
+	End        string `yaml:"end"`
+	Hourly     bool   `yaml:"hourly"`
+	Comment    string `yaml:"comment"`
+	Project    string `yaml:"project"`
+	Department string `yaml:"department"`
+	CostCenter string `yaml:"cost_center"`
+}
+
+// balanceConfig is the balance on the start date, before the reports of the date.
+type balanceConfig struct {
+	Start   string  `yaml:"start"`
+	Opening float64 `yaml:"opening"`
+}
+
+// dayOffConfig is either a single date or a range of dates. Dates without a year, like 24.12., repeat every year.
+type dayOffConfig struct {
+	Date        string `yaml:"date"`
+	Start       string `yaml:"start"`
+	End         string `yaml:"end"`
+	Description string `yaml:"description"`
+}
+
+// dayOff parses the dates of the day off.
+func (d dayOffConfig) dayOff() (hrflow.DayOff, error) {
+
+	start, end := d.Start, d.End
+	if len(d.Date) != 0 {
+		start, end = d.Date, d.Date
+	}
+	if len(start) == 0 || len(end) == 0 {
+		return hrflow.DayOff{}, errors.New("either date or start and end is required")
+	}
+
+	startDate, startYearly, err := parseConfigDate(start)
+	if err != nil {
+		return hrflow.DayOff{}, errors.Wrap(err, "parsing start date")
+	}
+	endDate, endYearly, err := parseConfigDate(end)
+	if err != nil {
+		return hrflow.DayOff{}, errors.Wrap(err, "parsing end date")
+	}
+	if startYearly != endYearly {
+		return hrflow.DayOff{}, errors.New("either both or neither of start and end must have a year")
+	}
+	if !startYearly && endDate.Before(startDate) {
+		return hrflow.DayOff{}, errors.New("end is before start")
+	}
+
+	return hrflow.DayOff{
+		Start:       startDate,
+		End:         endDate,
+		Yearly:      startYearly,
+		Description: d.Description,
+	}, nil
+}
+
+// parseConfigDate parses a date formatted as d.M.yyyy, or as d.M. for a date repeating every year.
+func parseConfigDate(s string) (time.Time, bool, error) {
+
+	date, err := time.ParseInLocation("2.1.2006", s, time.Local)
+	if err == nil {
+		return date, false, nil
+	}
+	date, err = time.ParseInLocation(dateLayout, s, time.Local)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return date, true, nil
 }
 
 func configPath() (string, error) {
@@ -43,7 +146,7 @@ func checkConfig(c *cli.Context) error {
 	return nil
 }
 
-func clientFromConfig() (*hrflow.Client, error) {
+func loadConfig() (*config, error) {
 
 	configPath, err := configPath()
 	if err != nil {
@@ -63,5 +166,134 @@ func clientFromConfig() (*hrflow.Client, error) {
 		return nil, errors.Wrap(err, "decoding config")
 	}
 
-	return hrflow.NewClient(cfg.Username, cfg.Password), nil
+	return &cfg, nil
+}
+
+// daysOff returns the days off declared in the config.
+func daysOff() ([]hrflow.DayOff, error) {
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var days []hrflow.DayOff
+	for i, d := range cfg.DaysOff {
+		day, err := d.dayOff()
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid days_off entry %d", i+1)
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
+
+func clientFromConfig() (*hrflow.Client, error) {
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	var options []hrflow.Option
+	if len(cfg.BaseURL) != 0 {
+		_, err = url.ParseRequestURI(cfg.BaseURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid base_url")
+		}
+		options = append(options, hrflow.WithBaseURL(cfg.BaseURL))
+	}
+	if len(cfg.LoginPath) != 0 {
+		options = append(options, hrflow.WithLoginPath(cfg.LoginPath))
+	}
+
+	client := hrflow.NewClient(cfg.Username, cfg.Password, options...)
+	client.EmploymentID = cfg.Employment
+
+	return client, nil
+}
+
+// newClient creates a client from the config and the global flags.
+func newClient(c *cli.Context) (*hrflow.Client, error) {
+
+	client, err := clientFromConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "creating client from config")
+	}
+	if c.IsSet("employment") {
+		client.EmploymentID = c.Int64("employment")
+	}
+	if cassetteTransport != nil {
+		client.HttpClient.Transport = cassetteTransport
+	}
+
+	return client, nil
+}
+
+// sessionClient returns a client with the stored session restored, if there is one. The client authenticates
+// when the backend first rejects the session, so commands that can be served from caches work offline.
+// Store the session with storeSession when done.
+func sessionClient(c *cli.Context) (*hrflow.Client, error) {
+
+	client, err := newClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// Recordings always go through the whole authentication process, so they don't depend on a stored session.
+	if cassetteTransport == nil {
+		_ = restoreSession(client)
+	}
+
+	return client, nil
+}
+
+// authenticatedClient returns a client with a session the backend accepts.
+func authenticatedClient(c *cli.Context) (*hrflow.Client, error) {
+
+	client, err := newClient(c)
+	if err != nil {
+		return nil, err
+	}
+
+	// A stored session saves running the whole authentication process. If the backend has expired it,
+	// checking it authenticates again.
+	if cassetteTransport == nil && restoreSession(client) == nil {
+		err = client.CheckSessionContext(c.Context)
+	} else {
+		err = client.AuthenticateContext(c.Context)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "authentication failed")
+	}
+
+	storeSession(client)
+
+	return client, nil
+}
+
+// storeSession stores the session of client for the next run. Not being able to store it only makes the next run
+// slower, so errors are only printed.
+func storeSession(client *hrflow.Client) {
+
+	if cassetteTransport != nil || len(client.Session().XSRFToken) == 0 {
+		return
+	}
+
+	err := saveSession(client)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "warning: storing session failed:", err)
+	}
+}
+
+// cacheDir returns the directory for the session and cached data.
+func cacheDir() (string, error) {
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", errors.Wrap(err, "getting cache directory")
+	}
+
+	return filepath.Join(dir, "hrflow"), nil
 }
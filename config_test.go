@@ -0,0 +1,80 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/myyra/hrflow/hrflow"
+)
+
+func TestDayOffConfig(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		config dayOffConfig
+		want   hrflow.DayOff
+	}{
+		{
+			"single date",
+			dayOffConfig{Date: "23.12.2026", Description: "Company day off"},
+			hrflow.DayOff{Start: day(2026, 12, 23), End: day(2026, 12, 23), Description: "Company day off"},
+		},
+		{
+			"yearly date",
+			dayOffConfig{Date: "23.12."},
+			hrflow.DayOff{Start: day(0, 12, 23), End: day(0, 12, 23), Yearly: true},
+		},
+		{
+			"range",
+			dayOffConfig{Start: "13.7.2026", End: "31.7.2026"},
+			hrflow.DayOff{Start: day(2026, 7, 13), End: day(2026, 7, 31)},
+		},
+		{
+			"yearly range over new year",
+			dayOffConfig{Start: "24.12.", End: "6.1."},
+			hrflow.DayOff{Start: day(0, 12, 24), End: day(0, 1, 6), Yearly: true},
+		},
+		{
+			"date overrides range",
+			dayOffConfig{Date: "1.6.2026", Start: "13.7.2026", End: "31.7.2026"},
+			hrflow.DayOff{Start: day(2026, 6, 1), End: day(2026, 6, 1)},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := test.config.dayOff()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !got.Start.Equal(test.want.Start) || !got.End.Equal(test.want.End) || got.Yearly != test.want.Yearly ||
+				got.Description != test.want.Description {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestDayOffConfigErrors(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		config dayOffConfig
+	}{
+		{"empty", dayOffConfig{Description: "Nothing"}},
+		{"start without end", dayOffConfig{Start: "13.7.2026"}},
+		{"end without start", dayOffConfig{End: "31.7.2026"}},
+		{"invalid date", dayOffConfig{Date: "31.2.2026"}},
+		{"invalid end", dayOffConfig{Start: "13.7.2026", End: "July"}},
+		{"yearly start and dated end", dayOffConfig{Start: "13.7.", End: "31.7.2026"}},
+		{"dated start and yearly end", dayOffConfig{Start: "13.7.2026", End: "31.7."}},
+		{"end before start", dayOffConfig{Start: "31.7.2026", End: "13.7.2026"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := test.config.dayOff(); err == nil {
+				t.Error("got no error")
+			}
+		})
+	}
+}
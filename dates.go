@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+// dateLayout is the layout used by all date flags.
+const dateLayout = "2.1."
+
+// dateFlag returns the date of the named flag in the current year, or def if the flag is not set.
+func dateFlag(c *cli.Context, name string, def time.Time) time.Time {
+
+	date := c.Timestamp(name)
+	if date == nil {
+		return midnight(def)
+	}
+
+	return time.Date(time.Now().Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// parseDate parses a date in dateLayout in the current year.
+func parseDate(s string) (time.Time, error) {
+
+	date, err := time.Parse(dateLayout, s)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Date(time.Now().Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local), nil
+}
+
+// midnight returns the start of the day of t.
+func midnight(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+}
+
+// periodFlags are the flags selecting the period of periodFlag.
+func periodFlags() []cli.Flag {
+
+	return []cli.Flag{
+		&cli.IntFlag{
+			Name:        "week",
+			Aliases:     []string{"w"},
+			Usage:       "ISO `WEEK` number of the current year",
+			DefaultText: "current week",
+		},
+		&cli.IntFlag{
+			Name:    "month",
+			Aliases: []string{"m"},
+			Usage:   "`MONTH` number of the current year, overrides --week",
+		},
+	}
+}
+
+// periodFlag returns the first and the last day of the week or month selected with periodFlags. Without flags,
+// the current week or month is returned depending on month.
+func periodFlag(c *cli.Context, month bool) (time.Time, time.Time, error) {
+
+	now := time.Now()
+
+	if c.IsSet("month") || (month && !c.IsSet("week")) {
+		m := int(now.Month())
+		if c.IsSet("month") {
+			m = c.Int("month")
+		}
+		if m < 1 || m > 12 {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid month %d", m)
+		}
+		start := time.Date(now.Year(), time.Month(m), 1, 0, 0, 0, 0, time.Local)
+		return start, start.AddDate(0, 1, -1), nil
+	}
+
+	// The current week can belong to the previous or the next year around New Year.
+	year, week := now.ISOWeek()
+	if c.IsSet("week") {
+		year, week = now.Year(), c.Int("week")
+	}
+
+	return weekPeriod(year, week)
+}
+
+// weekPeriod returns the first and the last day of the ISO week of year.
+func weekPeriod(year, week int) (time.Time, time.Time, error) {
+
+	start := isoWeekStart(year, week)
+	if y, w := start.ISOWeek(); y != year || w != week {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid week %d", week)
+	}
+
+	return start, start.AddDate(0, 0, 6), nil
+}
+
+// isoWeekStart returns the Monday of the ISO week of year.
+func isoWeekStart(year, week int) time.Time {
+
+	// January 4th is always in the first week.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.Local)
+	offset := (int(jan4.Weekday()) + 6) % 7
+
+	return jan4.AddDate(0, 0, -offset+(week-1)*7)
+}
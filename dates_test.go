@@ -0,0 +1,51 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekPeriodAroundNewYear(t *testing.T) {
+
+	tests := []struct {
+		now        time.Time
+		start, end time.Time
+	}{
+		{day(2025, 12, 28), day(2025, 12, 22), day(2025, 12, 28)},
+		{day(2025, 12, 29), day(2025, 12, 29), day(2026, 1, 4)},
+		{day(2026, 1, 1), day(2025, 12, 29), day(2026, 1, 4)},
+		{day(2027, 1, 1), day(2026, 12, 28), day(2027, 1, 3)},
+		{day(2027, 1, 3), day(2026, 12, 28), day(2027, 1, 3)},
+		{day(2027, 1, 4), day(2027, 1, 4), day(2027, 1, 10)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.now.Format("2.1.2006"), func(t *testing.T) {
+			start, end, err := weekPeriod(test.now.ISOWeek())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !start.Equal(test.start) || !end.Equal(test.end) {
+				t.Errorf("got %s-%s, want %s-%s", start.Format("2.1.2006"), end.Format("2.1.2006"),
+					test.start.Format("2.1.2006"), test.end.Format("2.1.2006"))
+			}
+		})
+	}
+}
+
+func TestWeekPeriodInvalidWeek(t *testing.T) {
+
+	for _, week := range []int{0, 53, 54} {
+		_, _, err := weekPeriod(2025, week)
+		if err == nil {
+			t.Errorf("got no error for week %d of 2025", week)
+		}
+	}
+	if _, _, err := weekPeriod(2026, 53); err != nil {
+		t.Errorf("got %v for week 53 of 2026", err)
+	}
+}
+
+func day(year, month, day int) time.Time {
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func deleteCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "delete",
+		Action: deleteReports,
+		Usage:  "delete hour reports",
+		Flags: []cli.Flag{
+			&cli.TimestampFlag{
+				Name:        "start",
+				Aliases:     []string{"s"},
+				Layout:      dateLayout,
+				Usage:       "first `DATE` to delete reports from, format 'd.M.'",
+				DefaultText: "today",
+			},
+			&cli.TimestampFlag{
+				Name:        "end",
+				Aliases:     []string{"e"},
+				Layout:      dateLayout,
+				Usage:       "last `DATE` to delete reports from, format 'd.M.'",
+				DefaultText: "start date",
+			},
+			&cli.Int64Flag{
+				Name:        "id",
+				Usage:       "only delete the report with `ID`, as printed by list.",
+				DefaultText: "all reports in the range",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "don't ask for confirmation",
+			},
+		},
+	}
+}
+
+func deleteReports(c *cli.Context) error {
+
+	start := dateFlag(c, "start", time.Now())
+	end := dateFlag(c, "end", start)
+	id := c.Int64("id")
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := client.WorkLogsContext(c.Context, start, end)
+	if err != nil {
+		return errors.Wrap(err, "getting work logs")
+	}
+
+	var deletable []hrflow.WorkLogRow
+	for _, row := range rows {
+		if id != 0 && row.Id != id {
+			continue
+		}
+		if row.Locked() {
+			fmt.Printf("skipping approved report %d\n", row.Id)
+			continue
+		}
+		deletable = append(deletable, row)
+	}
+
+	if len(deletable) == 0 {
+		fmt.Println("no reports to delete")
+		return nil
+	}
+
+	for _, row := range deletable {
+		day, err := row.Day()
+		if err != nil {
+			return errors.Wrap(err, "parsing work log date")
+		}
+		fmt.Println(day.Weekday(), day.Format("02.01.2006"))
+		printRow(row)
+	}
+
+	if !c.Bool("yes") {
+		ok, err := confirm(c.Context, fmt.Sprintf("delete %d reports?", len(deletable)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	for _, row := range deletable {
+		err = client.DeleteWorkLogContext(c.Context, row)
+		if err != nil {
+			return errors.Wrapf(err, "deleting report %d", row.Id)
+		}
+	}
+
+	fmt.Printf("%d reports deleted\n", len(deletable))
+
+	return nil
+}
+
+// confirm asks the user a yes or no question, defaulting to no. It returns the error of ctx if ctx is done
+// before the user answers.
+func confirm(ctx context.Context, question string) (bool, error) {
+
+	type result struct {
+		answer string
+		err    error
+	}
+
+	fmt.Printf("%s [y/N] ", question)
+
+	// Reading stdin can't be interrupted, so the read is left behind if ctx is done first.
+	answers := make(chan result, 1)
+	go func() {
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		answers <- result{answer, err}
+	}()
+
+	var r result
+	select {
+	case <-ctx.Done():
+		fmt.Println()
+		return false, ctx.Err()
+	case r = <-answers:
+	}
+	if r.err != nil {
+		return false, errors.Wrap(r.err, "reading answer")
+	}
+
+	answer := strings.ToLower(strings.TrimSpace(r.answer))
+
+	return answer == "y" || answer == "yes", nil
+}
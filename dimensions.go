@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func dimensionCommandFactory(name, usage, listID string) *cli.Command {
+
+	return &cli.Command{
+		Name:      name,
+		Usage:     usage,
+		ArgsUsage: "[SEARCH]",
+		Action: func(c *cli.Context) error {
+			return listDimension(c, listID)
+		},
+	}
+}
+
+func listDimension(c *cli.Context, listID string) error {
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	items, err := client.ListItemsContext(c.Context, listID)
+	if err != nil {
+		return errors.Wrapf(err, "getting list %s", listID)
+	}
+
+	if c.Args().Present() {
+		items = hrflow.SearchListItems(items, c.Args().First())
+	}
+
+	for _, item := range items {
+		fmt.Printf("%-10s %s\n", item.Value, item.Label)
+	}
+
+	return nil
+}
+
+// dimensionsFromFlags resolves the list items set with the dimension flags.
+func dimensionsFromFlags(c *cli.Context, client *hrflow.Client) (hrflow.Dimensions, error) {
+
+	var dimensions hrflow.Dimensions
+	var err error
+
+	dimensions.Department, err = resolveListItem(c.Context, client, hrflow.ListDepartments, c.String("department"))
+	if err != nil {
+		return dimensions, errors.Wrap(err, "invalid department, see the departments command")
+	}
+	dimensions.CostCenter, err = resolveListItem(c.Context, client, hrflow.ListCostCenters, c.String("cost-center"))
+	if err != nil {
+		return dimensions, errors.Wrap(err, "invalid cost center, see the cost-centers command")
+	}
+	dimensions.Project, err = resolveListItem(c.Context, client, hrflow.ListProjects, c.String("project"))
+	if err != nil {
+		return dimensions, errors.Wrap(err, "invalid project, see the projects command")
+	}
+
+	return dimensions, nil
+}
+
+// resolveListItem finds the item matching s from the list listID, or returns nil if s is empty.
+func resolveListItem(ctx context.Context, client *hrflow.Client, listID, s string) (*hrflow.ListItem, error) {
+
+	if len(s) == 0 {
+		return nil, nil
+	}
+
+	items, err := client.ListItemsContext(ctx, listID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting list %s", listID)
+	}
+
+	item, ok := hrflow.FindListItem(items, s)
+	if !ok {
+		return nil, fmt.Errorf("unknown value %s", s)
+	}
+
+	return &item, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func editCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "edit",
+		Action: edit,
+		Usage:  "edit an existing hour report",
+		Flags: []cli.Flag{
+			&cli.TimestampFlag{
+				Name:        "date",
+				Layout:      dateLayout,
+				Usage:       "`DATE` of the report to edit, format 'd.M.' (years not supported)",
+				DefaultText: "today",
+			},
+			&cli.Int64Flag{
+				Name:        "id",
+				Usage:       "`ID` of the report to edit, as printed by list. Required if there are several reports on the date.",
+				DefaultText: "the only report of the date",
+			},
+			&cli.TimestampFlag{
+				Name:    "start",
+				Aliases: []string{"s"},
+				Layout:  "15:04",
+				Usage:   "change workday start to `TIME`.",
+			},
+			&cli.TimestampFlag{
+				Name:    "end",
+				Aliases: []string{"e"},
+				Layout:  "15:04",
+				Usage:   "change workday end to `TIME`.",
+			},
+			&cli.StringFlag{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "change the `PROJECT` of the report, empty removes the project.",
+			},
+			&cli.StringFlag{
+				Name:  "department",
+				Usage: "change the `DEPARTMENT` of the report, empty removes the department.",
+			},
+			&cli.StringFlag{
+				Name:  "cost-center",
+				Usage: "change the `COST_CENTER` of the report, empty removes the cost center.",
+			},
+			&cli.StringFlag{
+				Name:    "comment",
+				Aliases: []string{"c"},
+				Usage:   "change the `COMMENT` of the report.",
+			},
+			&cli.BoolFlag{
+				Name:  "lunch",
+				Usage: "deduct a 30 minute lunch from the report, use --lunch=false to remove it.",
+			},
+		},
+	}
+}
+
+func edit(c *cli.Context) error {
+
+	date := dateFlag(c, "date", time.Now())
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := client.WorkLogsContext(c.Context, date, date)
+	if err != nil {
+		return errors.Wrap(err, "getting work logs")
+	}
+
+	row, err := findRow(rows, c.Int64("id"))
+	if err != nil {
+		return err
+	}
+
+	if c.IsSet("start") || c.IsSet("end") {
+		start, err := row.Start()
+		if err != nil {
+			return errors.Wrap(err, "parsing report start")
+		}
+		end, err := row.End()
+		if err != nil {
+			return errors.Wrap(err, "parsing report end")
+		}
+		if t := c.Timestamp("start"); t != nil {
+			start = time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		}
+		if t := c.Timestamp("end"); t != nil {
+			end = time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
+		}
+		if !end.After(start) {
+			return errors.New("report has to end after it starts")
+		}
+		row.SetTimes(start, end)
+	}
+	if c.IsSet("project") {
+		project, err := resolveListItem(c.Context, client, hrflow.ListProjects, c.String("project"))
+		if err != nil {
+			return errors.Wrap(err, "invalid project, see the projects command")
+		}
+		row.SetProject(project)
+	}
+	if c.IsSet("department") {
+		department, err := resolveListItem(c.Context, client, hrflow.ListDepartments, c.String("department"))
+		if err != nil {
+			return errors.Wrap(err, "invalid department, see the departments command")
+		}
+		row.SetDepartment(department)
+	}
+	if c.IsSet("cost-center") {
+		costCenter, err := resolveListItem(c.Context, client, hrflow.ListCostCenters, c.String("cost-center"))
+		if err != nil {
+			return errors.Wrap(err, "invalid cost center, see the cost-centers command")
+		}
+		row.SetCostCenter(costCenter)
+	}
+	if c.IsSet("comment") {
+		comment := c.String("comment")
+		row.EntryText = &comment
+	}
+	if c.IsSet("lunch") {
+		row.SetLunch(c.Bool("lunch"))
+	}
+
+	err = client.UpdateWorkLogContext(c.Context, row)
+	if err != nil {
+		return errors.Wrap(err, "updating work log")
+	}
+
+	fmt.Println("report updated")
+
+	return nil
+}
+
+// findRow returns the row with the given id, or the only row if id is 0.
+func findRow(rows []hrflow.WorkLogRow, id int64) (hrflow.WorkLogRow, error) {
+
+	if id == 0 {
+		switch len(rows) {
+		case 0:
+			return hrflow.WorkLogRow{}, errors.New("no reports found for the date")
+		case 1:
+			return rows[0], nil
+		default:
+			return hrflow.WorkLogRow{}, fmt.Errorf("found %d reports for the date, select one with --id", len(rows))
+		}
+	}
+
+	for _, row := range rows {
+		if row.Id == id {
+			return row, nil
+		}
+	}
+
+	return hrflow.WorkLogRow{}, fmt.Errorf("report %d not found for the date", id)
+}
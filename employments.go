@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func employmentsCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "employments",
+		Action: employments,
+		Usage:  "list your employments",
+	}
+}
+
+func employments(c *cli.Context) error {
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	// The selected employment is marked, if there is one.
+	selected, _ := client.Employment(time.Now())
+
+	for _, employment := range client.Employments() {
+		marker := " "
+		if employment.EmploymentID == selected.EmploymentID {
+			marker = "*"
+		}
+		var end string
+		if employment.EndDate != nil {
+			end = *employment.EndDate
+		}
+		var status string
+		switch {
+		case employment.IsPassive:
+			status = "passive"
+		case employment.IsDefaultEmployment:
+			status = "default"
+		}
+		fmt.Printf("%s %d  %s  %s  %s - %s  %s\n", marker, employment.EmploymentID, employment.EnterpriseName, employment.Name, employment.StartDate, end, status)
+	}
+
+	return nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+// Exit codes for errors the user can act on. Other errors exit with 1.
+const (
+	exitMissingDays        = 2
+	exitInvalidCredentials = 3
+	exitSessionExpired     = 4
+	exitNoEmployment       = 5
+	exitValidation         = 6
+	exitUnexpectedPage     = 7
+	exitInterrupted        = 130
+)
+
+// explain returns a message telling what to do about err and the exit code for it.
+func explain(err error) (string, int) {
+
+	var validationErr *hrflow.ValidationError
+	var exitCoder cli.ExitCoder
+
+	switch {
+	case errors.As(err, &exitCoder):
+		return err.Error(), exitCoder.ExitCode()
+	case errors.Is(err, hrflow.ErrInvalidCredentials):
+		return "login failed, check the username and password in ~/.hrflow", exitInvalidCredentials
+	case errors.Is(err, hrflow.ErrSessionExpired):
+		return "session expired and logging in again failed, try again", exitSessionExpired
+	case errors.Is(err, hrflow.ErrNoEmployment):
+		return fmt.Sprintf("%v\nsee `hrflow employments` and select an employment with --employment", err), exitNoEmployment
+	case errors.As(err, &validationErr):
+		if len(validationErr.Messages) == 0 {
+			return "HR Flow refused the change without giving a reason", exitValidation
+		}
+		return "HR Flow refused the change:\n  " + strings.Join(validationErr.Messages, "\n  "), exitValidation
+	case errors.Is(err, hrflow.ErrUnexpectedPage):
+		return fmt.Sprintf("%v\nthe HR Flow login has probably changed, please open an issue at https://github.com/myyra/hrflow/issues", err), exitUnexpectedPage
+	case errors.Is(err, context.Canceled):
+		return "interrupted", exitInterrupted
+	}
+
+	return err.Error(), 1
+}
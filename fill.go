@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func fillCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "fill",
+		Action: fill,
+		Usage:  "report the default entry from the config to workdays without reports",
+		Flags: append(periodFlags(),
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "don't ask for confirmation",
+			},
+		),
+	}
+}
+
+func fill(c *cli.Context) error {
+
+	start, end, err := periodFlag(c, false)
+	if err != nil {
+		return err
+	}
+	// Only days that have passed are filled, like the missing command lists them.
+	if yesterday := midnight(time.Now()).AddDate(0, 0, -1); end.After(yesterday) {
+		end = yesterday
+	}
+	if end.Before(start) {
+		fmt.Println("no workdays to fill")
+		return nil
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	startTime, endTime, err := fillTimes(cfg.Fill)
+	if err != nil {
+		return err
+	}
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	missing, err := missingWorkdays(c, client, start, end)
+	if err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		fmt.Println("no workdays to fill")
+		return nil
+	}
+
+	dimensions, err := fillDimensions(c, client, cfg.Fill)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s-%s %s\n", startTime.Format("15:04"), endTime.Format("15:04"), cfg.Fill.Comment)
+	for _, date := range missing {
+		fmt.Println(date.Weekday(), date.Format("02.01.2006"))
+	}
+	if !c.Bool("yes") {
+		ok, err := confirm(c.Context, fmt.Sprintf("report to %d days?", len(missing)))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return nil
+		}
+	}
+
+	date := missing[0]
+	startTime = time.Date(date.Year(), date.Month(), date.Day(), startTime.Hour(), startTime.Minute(), 0, 0, time.Local)
+	endTime = time.Date(date.Year(), date.Month(), date.Day(), endTime.Hour(), endTime.Minute(), 0, 0, time.Local)
+	// Lunch is only applicable for monthly workers.
+	lunch := !cfg.Fill.Hourly
+
+	err = client.NewWorkLogWithDimensionsContext(c.Context, startTime, endTime, salaryGroup(cfg.Fill.Hourly), cfg.Fill.Comment, dimensions, lunch, missing[1:]...)
+	if err != nil {
+		return errors.Wrap(err, "creating work log")
+	}
+
+	fmt.Printf("report logged to %d dates\n", len(missing))
+
+	return nil
+}
+
+// missingWorkdays returns the workdays between start and end without reports.
+func missingWorkdays(c *cli.Context, client *hrflow.Client, start, end time.Time) ([]time.Time, error) {
+
+	workCal, err := workCalendar(c, client, start, end)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := client.WorkLogsContext(c.Context, start, end)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting work logs")
+	}
+
+	return hrflow.MissingWorkdays(rows, workCal, start, end)
+}
+
+// fillTimes parses the start and end times of the fill entry, defaulting to 8:00-16:00.
+func fillTimes(cfg fillConfig) (time.Time, time.Time, error) {
+
+	start, end := "8:00", "16:00"
+	if len(cfg.Start) != 0 {
+		start = cfg.Start
+	}
+	if len(cfg.End) != 0 {
+		end = cfg.End
+	}
+
+	startTime, err := time.Parse("15:04", start)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrap(err, "invalid fill.start")
+	}
+	endTime, err := time.Parse("15:04", end)
+	if err != nil {
+		return time.Time{}, time.Time{}, errors.Wrap(err, "invalid fill.end")
+	}
+	if !endTime.After(startTime) {
+		return time.Time{}, time.Time{}, errors.New("fill.end must be after fill.start")
+	}
+
+	return startTime, endTime, nil
+}
+
+// fillDimensions resolves the list items of the fill entry.
+func fillDimensions(c *cli.Context, client *hrflow.Client, cfg fillConfig) (hrflow.Dimensions, error) {
+
+	var dimensions hrflow.Dimensions
+	var err error
+
+	dimensions.Department, err = resolveListItem(c.Context, client, hrflow.ListDepartments, cfg.Department)
+	if err != nil {
+		return dimensions, errors.Wrap(err, "invalid fill.department, see the departments command")
+	}
+	dimensions.CostCenter, err = resolveListItem(c.Context, client, hrflow.ListCostCenters, cfg.CostCenter)
+	if err != nil {
+		return dimensions, errors.Wrap(err, "invalid fill.cost_center, see the cost-centers command")
+	}
+	dimensions.Project, err = resolveListItem(c.Context, client, hrflow.ListProjects, cfg.Project)
+	if err != nil {
+		return dimensions, errors.Wrap(err, "invalid fill.project, see the projects command")
+	}
+
+	return dimensions, nil
+}
@@ -1,12 +1,12 @@
 package hrflow
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"fmt"
 	"io/ioutil"
-	"log"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -15,146 +15,119 @@ import (
 
 // Authenticate runs the authentication process and stores the needed cookies and header values.
 func (c *Client) Authenticate() error {
+	return c.AuthenticateContext(context.Background())
+}
 
-	start := "https://hrflow.accountor.fi/KirjaamoWeb/login/Employee"
+// AuthenticateContext is like Authenticate but the requests are bound to ctx.
+func (c *Client) AuthenticateContext(ctx context.Context) error {
 
-	req, err := http.NewRequest("GET", start, nil)
-	if err != nil {
-		return errors.Wrap(err, "creating initial request")
-	}
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
 
-	resp, err := c.HttpClient.Do(req)
-	if err != nil {
-		return errors.Wrap(err, "doing initial request")
-	}
-	defer resp.Body.Close()
-	body, _ := ioutil.ReadAll(resp.Body)
-	bodyString := string(body)
+	return c.authenticate(ctx)
+}
 
-	loginURLRegex := regexp.MustCompile("id=\"options\".*action=\"(.*)\"")
-	loginURL := loginURLRegex.FindAllStringSubmatch(bodyString, -1)[0][1]
+// reauthenticate authenticates again after a request made with the session generation was rejected.
+// If another goroutine has already replaced that session, its session is used instead.
+func (c *Client) reauthenticate(ctx context.Context, generation uint64) error {
 
-	loginBody := url.Values{}
-	loginBody.Add("UserName", c.username)
-	loginBody.Add("Password", c.password)
-	loginBody.Add("AuthMethod", "FormsAuthentication")
-	loginReq, err := http.NewRequest("POST", loginURL, strings.NewReader(loginBody.Encode()))
-	if err != nil {
-		return errors.Wrap(err, "creating login request")
-	}
-	loginReq.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
-	loginResp, err := c.HttpClient.Do(loginReq)
-	if err != nil {
-		return errors.Wrap(err, "doing login request")
-	}
-	defer loginResp.Body.Close()
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
 
-	doc, err := goquery.NewDocumentFromReader(loginResp.Body)
-	if err != nil {
-		return errors.Wrap(err, "creating goquery document from login response")
+	if _, current := c.session(); current != generation {
+		return nil
 	}
 
-	authURL, err := formActionURL(doc)
+	return c.authenticate(ctx)
+}
+
+// authenticate goes through the login process one page at a time:
+// the login page, the credentials form, the auth form, the OIDC form and finally the logged in page.
+func (c *Client) authenticate(ctx context.Context) error {
+
+	loginPage, err := c.fetchPage(ctx, "GET", c.endpoint(c.loginPath), nil)
 	if err != nil {
-		return errors.Wrap(err, "getting auth form action")
+		return errors.Wrap(err, "loading login page")
 	}
-
-	authBody, err := formValues(doc)
+	loginURL, err := loginFormURL(loginPage)
 	if err != nil {
-		return errors.Wrap(err, "getting auth form values")
+		return errors.Wrap(err, "parsing login page")
 	}
 
-	authReq, err := http.NewRequest("POST", authURL, strings.NewReader(authBody.Encode()))
+	credentials := url.Values{}
+	credentials.Add("UserName", c.username)
+	credentials.Add("Password", c.password)
+	credentials.Add("AuthMethod", "FormsAuthentication")
+	authPage, err := c.fetchPage(ctx, "POST", loginURL, credentials)
 	if err != nil {
-		log.Println(err)
+		return errors.Wrap(err, "sending credentials")
 	}
-	authReq.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
-
-	authResp, err := c.HttpClient.Do(authReq)
+	err = checkLoginRejected(authPage)
 	if err != nil {
-		log.Println(err)
+		return err
 	}
-	defer authResp.Body.Close()
-
-	doc, err = goquery.NewDocumentFromReader(authResp.Body)
+	authForm, err := firstForm(authPage)
 	if err != nil {
-		return errors.Wrap(err, "creating goquery document from auth response")
+		return errors.Wrap(err, "parsing auth page")
 	}
 
-	oidcURL, err := formActionURL(doc)
+	oidcPage, err := c.fetchPage(ctx, "POST", authForm.action, authForm.values)
 	if err != nil {
-		return errors.Wrap(err, "getting auth form action")
+		return errors.Wrap(err, "sending auth form")
 	}
-
-	oidcBody, err := formValues(doc)
+	oidcForm, err := firstForm(oidcPage)
 	if err != nil {
-		return errors.Wrap(err, "getting auth form values")
+		return errors.Wrap(err, "parsing oidc page")
 	}
 
-	oidcReq, err := http.NewRequest("POST", oidcURL, strings.NewReader(oidcBody.Encode()))
+	loggedInPage, err := c.fetchPage(ctx, "POST", oidcForm.action, oidcForm.values)
 	if err != nil {
-		return errors.Wrap(err, "creating oidc request")
+		return errors.Wrap(err, "sending oidc form")
 	}
-	oidcReq.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
-	oidcResp, err := c.HttpClient.Do(oidcReq)
+	session, err := parseLoggedInPage(loggedInPage)
 	if err != nil {
-		return errors.Wrap(err, "doing oidc request")
+		return errors.Wrap(err, "parsing logged in page")
 	}
-	defer oidcResp.Body.Close()
-	loggedInBody, _ := ioutil.ReadAll(oidcResp.Body)
-	loggedInContent := string(loggedInBody)
 
-	rvtRegex := regexp.MustCompile("var RequestVerificationToken = '(.*)'")
-	rvt := rvtRegex.FindAllStringSubmatch(loggedInContent, -1)[0][1]
+	c.setSession(session.XSRFToken, session.UserRoleKey, session.Employments)
+
+	return nil
+}
 
-	usrRegex := regexp.MustCompile("var SELECTED_USER_AND_ROLEKEY = \"(.*)\";")
-	usr := usrRegex.FindAllStringSubmatch(loggedInContent, -1)[0][1]
+// fetchPage requests an HTML page, posting values as a form if they aren't nil.
+func (c *Client) fetchPage(ctx context.Context, method, pageURL string, values url.Values) (*page, error) {
 
-	employmentsRegex := regexp.MustCompile(`var employments = (\[{.*}\])`)
-	employmentsString := employmentsRegex.FindAllStringSubmatch(loggedInContent, -1)[0][1]
-	var employments []Employment
-	err = json.Unmarshal([]byte(employmentsString), &employments)
+	req, err := http.NewRequestWithContext(ctx, method, pageURL, strings.NewReader(values.Encode()))
 	if err != nil {
-		return errors.Wrap(err, "parsing employments")
+		return nil, errors.Wrap(err, "creating request")
+	}
+	if values != nil {
+		req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
 	}
 
-	c.userRoleKey = usr
-	c.xsrfToken = rvt
-	c.Employments = employments
-
-	return nil
-}
+	c.logger.Printf("%s %s", method, pageURL)
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "doing request")
+	}
+	defer resp.Body.Close()
 
-// formActionURL returns the action URL from the first form of the document.
-func formActionURL(doc *goquery.Document) (string, error) {
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("http status error %d %s", resp.StatusCode, resp.Status)
+	}
 
-	authForm := doc.Find("form").First()
-	authURL, exists := authForm.Attr("action")
-	if !exists {
-		return "", errors.New("form action attribute not found")
+	content, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response")
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing html")
 	}
-	return authURL, nil
-}
 
-// formValues returns the name and value of all input tags from the first form of the document.
-func formValues(doc *goquery.Document) (url.Values, error) {
-
-	var err error = nil
-
-	values := url.Values{}
-	doc.Find("input").Each(func(i int, s *goquery.Selection) {
-		name, exists := s.Attr("name")
-		if !exists {
-			err = errors.New("getting form input tag name")
-			return
-		}
-		value, exists := s.Attr("value")
-		if !exists {
-			err = errors.New("getting form input tag value")
-			return
-		}
-		values.Add(name, value)
-	})
-
-	return values, err
+	return &page{
+		url:     resp.Request.URL,
+		doc:     doc,
+		content: string(content),
+	}, nil
 }
@@ -0,0 +1,56 @@
+package hrflow
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// BalanceDay is a day of a flex-time balance.
+type BalanceDay struct {
+	// Date is the midnight of the day.
+	Date time.Time
+	// Worked is the sum of the worked hours of the rows of the day.
+	Worked float64
+	// Expected is the daily hours on workdays and zero on other days.
+	Expected float64
+	// Balance is the balance at the end of the day.
+	Balance float64
+}
+
+// FlexBalance calculates the flex-time balance for each day between startDate and endDate, inclusive, starting from
+// opening. Every workday of workCal is expected to be dailyHours long. Rejected rows are not counted.
+func FlexBalance(rows []WorkLogRow, workCal *WorkCalendar, startDate, endDate time.Time, opening, dailyHours float64) ([]BalanceDay, error) {
+
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, time.Local)
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, time.Local)
+
+	worked := map[string]float64{}
+	for _, row := range rows {
+		if row.Status == StatusRejected {
+			continue
+		}
+		day, err := row.Day()
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing work log date")
+		}
+		worked[dateKey(day)] += row.WorkedHours()
+	}
+
+	var days []BalanceDay
+	balance := opening
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		day := BalanceDay{
+			Date:   date,
+			Worked: worked[dateKey(date)],
+		}
+		if workCal.IsWorkday(date) {
+			day.Expected = dailyHours
+		}
+		balance += day.Worked - day.Expected
+		day.Balance = balance
+		days = append(days, day)
+	}
+
+	return days, nil
+}
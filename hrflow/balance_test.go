@@ -0,0 +1,62 @@
+package hrflow
+
+import (
+	"testing"
+	"time"
+)
+
+// newRow returns a row on date from startHour to endHour with the given lunch and status.
+func newRow(date time.Time, startHour, endHour int, lunch bool, status string) WorkLogRow {
+
+	row := WorkLogRow{SalaryGroupValue: "99002", Status: status}
+	row.SetTimes(date.Add(time.Duration(startHour)*time.Hour), date.Add(time.Duration(endHour)*time.Hour))
+	row.SetLunch(lunch)
+
+	return row
+}
+
+func TestFlexBalance(t *testing.T) {
+
+	calendar := newWorkCalendar(t)
+	rows := []WorkLogRow{
+		newRow(newDate(2026, time.April, 1), 8, 16, true, StatusApproved),
+		newRow(newDate(2026, time.April, 2), 8, 17, true, StatusSent),
+		newRow(newDate(2026, time.April, 2), 17, 19, false, StatusRejected),
+		// Good Friday.
+		newRow(newDate(2026, time.April, 3), 10, 12, false, StatusNew),
+		newRow(newDate(2026, time.April, 7), 8, 15, true, StatusNew),
+	}
+
+	days, err := FlexBalance(rows, calendar, newDate(2026, time.April, 1), newDate(2026, time.April, 7), 1, 7.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []BalanceDay{
+		{newDate(2026, time.April, 1), 7.5, 7.5, 1},
+		{newDate(2026, time.April, 2), 8.5, 7.5, 2},
+		{newDate(2026, time.April, 3), 2, 0, 4},
+		{newDate(2026, time.April, 4), 0, 0, 4},
+		{newDate(2026, time.April, 5), 0, 0, 4},
+		{newDate(2026, time.April, 6), 0, 0, 4},
+		{newDate(2026, time.April, 7), 6.5, 7.5, 3},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, day := range days {
+		if !day.Date.Equal(want[i].Date) || day.Worked != want[i].Worked || day.Expected != want[i].Expected ||
+			day.Balance != want[i].Balance {
+			t.Errorf("got %+v, want %+v", day, want[i])
+		}
+	}
+}
+
+func TestFlexBalanceInvalidDate(t *testing.T) {
+
+	_, err := FlexBalance([]WorkLogRow{{Date: "2.4.2026"}}, newWorkCalendar(t), newDate(2026, time.April, 1),
+		newDate(2026, time.April, 7), 0, 7.5)
+	if err == nil {
+		t.Error("got no error for an invalid date")
+	}
+}
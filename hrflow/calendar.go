@@ -1,12 +1,11 @@
 package hrflow
 
 import (
-	"encoding/json"
-	"fmt"
-	"log"
+	"context"
 	"net/http"
 	"net/url"
-	"strings"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -81,31 +80,106 @@ func (d *hrCalendarDay) calendarDay() (CalendarDay, error) {
 	}, nil
 }
 
+// calendarChunkDays is the longest range fetched with a single calendar request. Longer ranges are split.
+const calendarChunkDays = 62
+
+// calendarConcurrency is the most calendar requests done at the same time.
+const calendarConcurrency = 4
+
+// Calendar returns the days between startDate and endDate, inclusive, sorted by date.
+// Long ranges are fetched in several requests, up to four at a time.
 func (c *Client) Calendar(startDate, endDate time.Time) ([]CalendarDay, error) {
+	return c.CalendarContext(context.Background(), startDate, endDate)
+}
 
-	getCalendarBody := url.Values{}
-	getCalendarBody.Add("startDate", startDate.Format(hrFlowDateFormat))
-	getCalendarBody.Add("endDate", endDate.Format(hrFlowDateFormat))
-	getCalendarRequest, _ := http.NewRequest("GET", "https://hrflow.accountor.fi/KirjaamoWeb/calendar/GetCalendar", strings.NewReader(getCalendarBody.Encode()))
-	getCalendarRequest.Header.Add("X-XSRF-TOKEN", c.xsrfToken)
-	getCalendarRequest.Header.Add("Accept", "application/json")
-	getCalendarRequest.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+// CalendarContext is like Calendar but the requests are bound to ctx.
+func (c *Client) CalendarContext(ctx context.Context, startDate, endDate time.Time) ([]CalendarDay, error) {
 
-	resp, err := c.HttpClient.Do(getCalendarRequest)
-	if err != nil {
-		log.Println(err)
+	type chunk struct {
+		start, end time.Time
+		days       []CalendarDay
+		err        error
+	}
+
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, endDate.Location())
+
+	var chunks []*chunk
+	for start := startDate; !start.After(endDate); start = start.AddDate(0, 0, calendarChunkDays) {
+		end := start.AddDate(0, 0, calendarChunkDays-1)
+		if end.After(endDate) {
+			end = endDate
+		}
+		chunks = append(chunks, &chunk{start: start, end: end})
+	}
+
+	// The first failure cancels the other requests.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, calendarConcurrency)
+	for _, ch := range chunks {
+		wg.Add(1)
+		go func(ch *chunk) {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			ch.days, ch.err = c.calendarChunk(ctx, ch.start, ch.end)
+			if ch.err != nil {
+				cancel()
+			}
+		}(ch)
+	}
+	wg.Wait()
+
+	// The error that caused the cancellation is more interesting than the cancellations.
+	for _, ch := range chunks {
+		if ch.err != nil && !errors.Is(ch.err, context.Canceled) {
+			return nil, ch.err
+		}
+	}
+	for _, ch := range chunks {
+		if ch.err != nil {
+			return nil, ch.err
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("http status error %s", resp.Status)
+	// Merged chunks shouldn't overlap, but days are deduplicated by date in case the backend returns extra days.
+	byDate := map[string]CalendarDay{}
+	for _, ch := range chunks {
+		for _, day := range ch.days {
+			byDate[day.Date.Format("2006-01-02")] = day
+		}
+	}
+
+	days := []CalendarDay{}
+	for _, day := range byDate {
+		days = append(days, day)
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+
+	return days, nil
+}
+
+// calendarChunk fetches the days between startDate and endDate with a single request.
+func (c *Client) calendarChunk(ctx context.Context, startDate, endDate time.Time) ([]CalendarDay, error) {
+
+	query := url.Values{}
+	query.Add("startDate", startDate.Format(hrFlowDateFormat))
+	query.Add("endDate", endDate.Format(hrFlowDateFormat))
+	getCalendarRequest, err := http.NewRequestWithContext(ctx, "GET", c.endpoint(CalendarPath)+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating calendar request")
 	}
 
 	var response []hrCalendarDay
 
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	err = c.doJSON(getCalendarRequest, &response)
 	if err != nil {
-		return nil, errors.Wrap(err, "decoding calendar response")
+		return nil, errors.Wrapf(err, "calendar request %s-%s", query.Get("startDate"), query.Get("endDate"))
 	}
 
 	days := []CalendarDay{}
@@ -0,0 +1,164 @@
+package hrflow
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CalendarSource provides calendar days. Client is the main implementation.
+type CalendarSource interface {
+	CalendarContext(ctx context.Context, startDate, endDate time.Time) ([]CalendarDay, error)
+}
+
+// CalendarCache stores the days fetched from Source to a file and uses them instead of fetching again until they
+// are older than TTL. If Source fails, cached days are used regardless of their age, so the calendar works offline.
+type CalendarCache struct {
+	Source CalendarSource
+	// Path is the cache file, created if it doesn't exist.
+	Path string
+	// Key separates calendars stored in the same file, such as the calendars of different employments.
+	Key string
+	// TTL is how long cached days are used before fetching them again.
+	TTL time.Duration
+	// Refresh makes the cache fetch from Source even if the cached days are fresh.
+	Refresh bool
+	// Clock returns the current time used for the age of the days. If nil, time.Now is used.
+	Clock func() time.Time
+
+	mu sync.Mutex
+}
+
+type cachedDay struct {
+	Day     CalendarDay `json:"day"`
+	Fetched time.Time   `json:"fetched"`
+}
+
+// calendarCacheFile maps keys to days by date.
+type calendarCacheFile map[string]map[string]cachedDay
+
+// Calendar returns the days between startDate and endDate, inclusive, from the cache or Source.
+func (c *CalendarCache) Calendar(startDate, endDate time.Time) ([]CalendarDay, error) {
+	return c.CalendarContext(context.Background(), startDate, endDate)
+}
+
+// CalendarContext is like Calendar but the requests to Source are bound to ctx.
+func (c *CalendarCache) CalendarContext(ctx context.Context, startDate, endDate time.Time) ([]CalendarDay, error) {
+
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, endDate.Location())
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	file, err := c.load()
+	if err != nil {
+		return nil, err
+	}
+	cached := file[c.Key]
+
+	days, fresh := cachedDays(cached, startDate, endDate, c.now().Add(-c.TTL))
+	if days != nil && fresh && !c.Refresh {
+		return days, nil
+	}
+
+	fetched, err := c.Source.CalendarContext(ctx, startDate, endDate)
+	if err != nil {
+		if days != nil && !errors.Is(err, context.Canceled) {
+			return days, nil
+		}
+		return nil, err
+	}
+
+	if cached == nil {
+		cached = map[string]cachedDay{}
+		file[c.Key] = cached
+	}
+	now := c.now()
+	for _, day := range fetched {
+		cached[day.Date.Format("2006-01-02")] = cachedDay{Day: day, Fetched: now}
+	}
+
+	err = c.save(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetched, nil
+}
+
+// now returns the current time from Clock.
+func (c *CalendarCache) now() time.Time {
+
+	if c.Clock == nil {
+		return time.Now()
+	}
+
+	return c.Clock()
+}
+
+// cachedDays returns the cached days between startDate and endDate, or nil if any of them is missing.
+// fresh tells if all of them have been fetched after freshAfter.
+func cachedDays(cached map[string]cachedDay, startDate, endDate, freshAfter time.Time) (days []CalendarDay, fresh bool) {
+
+	fresh = true
+	days = []CalendarDay{}
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		day, ok := cached[date.Format("2006-01-02")]
+		if !ok {
+			return nil, false
+		}
+		if day.Fetched.Before(freshAfter) {
+			fresh = false
+		}
+		days = append(days, day.Day)
+	}
+
+	return days, fresh
+}
+
+func (c *CalendarCache) load() (calendarCacheFile, error) {
+
+	content, err := ioutil.ReadFile(c.Path)
+	if os.IsNotExist(err) {
+		return calendarCacheFile{}, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading calendar cache")
+	}
+
+	file := calendarCacheFile{}
+
+	// A broken cache is replaced on the next save.
+	if json.Unmarshal(content, &file) != nil {
+		return calendarCacheFile{}, nil
+	}
+
+	return file, nil
+}
+
+func (c *CalendarCache) save(file calendarCacheFile) error {
+
+	err := os.MkdirAll(filepath.Dir(c.Path), 0700)
+	if err != nil {
+		return errors.Wrap(err, "creating calendar cache directory")
+	}
+
+	content, err := json.Marshal(file)
+	if err != nil {
+		return errors.Wrap(err, "marshaling calendar cache")
+	}
+
+	err = ioutil.WriteFile(c.Path, content, 0600)
+	if err != nil {
+		return errors.Wrap(err, "writing calendar cache")
+	}
+
+	return nil
+}
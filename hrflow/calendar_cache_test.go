@@ -0,0 +1,172 @@
+package hrflow
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingSource counts the calls to the Finnish calendar, and fails them if err is set.
+type countingSource struct {
+	calls int
+	err   error
+}
+
+func (s *countingSource) CalendarContext(ctx context.Context, startDate, endDate time.Time) ([]CalendarDay, error) {
+
+	s.calls++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	return FinnishCalendar{}.CalendarContext(ctx, startDate, endDate)
+}
+
+// cacheTest is a cache in a temporary directory with a clock the test controls.
+type cacheTest struct {
+	t      *testing.T
+	dir    string
+	now    time.Time
+	source *countingSource
+	cache  *CalendarCache
+}
+
+func newCacheTest(t *testing.T) *cacheTest {
+
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "hrflow")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test := &cacheTest{
+		t:      t,
+		dir:    dir,
+		now:    time.Date(2020, time.March, 2, 12, 0, 0, 0, time.Local),
+		source: &countingSource{},
+	}
+	test.cache = test.newCache("1")
+
+	return test
+}
+
+// newCache returns another cache for key in the same file.
+func (c *cacheTest) newCache(key string) *CalendarCache {
+	return &CalendarCache{
+		Source: c.source,
+		Path:   filepath.Join(c.dir, "calendar.json"),
+		Key:    key,
+		TTL:    24 * time.Hour,
+		Clock: func() time.Time {
+			return c.now
+		},
+	}
+}
+
+// fetch gets the week starting from the first day of the test from cache, and checks how many times the source
+// has been called.
+func (c *cacheTest) fetch(cache *CalendarCache, wantCalls int) {
+
+	c.t.Helper()
+
+	start := time.Date(2020, time.March, 2, 0, 0, 0, 0, time.Local)
+	days, err := cache.Calendar(start, start.AddDate(0, 0, 6))
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if len(days) != 7 {
+		c.t.Errorf("got %d days, want 7", len(days))
+	}
+	if c.source.calls != wantCalls {
+		c.t.Errorf("source was called %d times, want %d", c.source.calls, wantCalls)
+	}
+}
+
+func (c *cacheTest) close() {
+	os.RemoveAll(c.dir)
+}
+
+func TestCalendarCacheTTL(t *testing.T) {
+
+	test := newCacheTest(t)
+	defer test.close()
+
+	test.fetch(test.cache, 1)
+	// Fresh days come from the cache.
+	test.now = test.now.Add(23 * time.Hour)
+	test.fetch(test.cache, 1)
+	// Old days are fetched again.
+	test.now = test.now.Add(2 * time.Hour)
+	test.fetch(test.cache, 2)
+	test.fetch(test.cache, 2)
+}
+
+func TestCalendarCacheRefresh(t *testing.T) {
+
+	test := newCacheTest(t)
+	defer test.close()
+
+	test.fetch(test.cache, 1)
+	test.cache.Refresh = true
+	test.fetch(test.cache, 2)
+}
+
+func TestCalendarCacheOffline(t *testing.T) {
+
+	test := newCacheTest(t)
+	defer test.close()
+
+	test.fetch(test.cache, 1)
+
+	// Old days are used if fetching fails.
+	test.now = test.now.Add(48 * time.Hour)
+	test.source.err = errors.New("offline")
+	test.fetch(test.cache, 2)
+
+	// Days that were never cached can't be used offline.
+	date := time.Date(2020, time.March, 9, 0, 0, 0, 0, time.Local)
+	_, err := test.cache.Calendar(date, date)
+	if err == nil {
+		t.Error("got no error for days missing from the cache")
+	}
+
+	// Cancelling is not mistaken for being offline, even if there are old days.
+	test.source.err = context.Canceled
+	_, err = test.cache.Calendar(date.AddDate(0, 0, -7), date.AddDate(0, 0, -1))
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestCalendarCacheKeys(t *testing.T) {
+
+	test := newCacheTest(t)
+	defer test.close()
+
+	test.fetch(test.cache, 1)
+	// Another employment has its own days in the same file.
+	other := test.newCache("2")
+	test.fetch(other, 2)
+	test.fetch(other, 2)
+	test.fetch(test.cache, 2)
+}
+
+func TestCalendarCacheBrokenFile(t *testing.T) {
+
+	test := newCacheTest(t)
+	defer test.close()
+
+	err := ioutil.WriteFile(test.cache.Path, []byte("{broken"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A broken file is replaced.
+	test.fetch(test.cache, 1)
+	test.fetch(test.cache, 1)
+}
@@ -0,0 +1,78 @@
+package hrflow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCalendarChunks(t *testing.T) {
+
+	var mu sync.Mutex
+	inFlight, maxInFlight, requests := 0, 0, 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		mu.Lock()
+		inFlight++
+		requests++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+		}()
+		// Gives the other requests time to start.
+		time.Sleep(10 * time.Millisecond)
+
+		start, err := time.Parse(hrFlowDateFormat, r.URL.Query().Get("startDate"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		end, err := time.Parse(hrFlowDateFormat, r.URL.Query().Get("endDate"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		days := []hrCalendarDay{}
+		for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+			days = append(days, hrCalendarDay{
+				Date:    date.Format("2006-01-02T15:04:05"),
+				Workday: date.Weekday() != time.Saturday && date.Weekday() != time.Sunday,
+				Weekday: "1",
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(days)
+	}))
+	defer server.Close()
+
+	client := NewClient("user", "pass", WithBaseURL(server.URL))
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.Local)
+	days, err := client.Calendar(start, start.AddDate(2, 0, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(days) != 731 {
+		t.Errorf("got %d days, want 731", len(days))
+	}
+	for i := 1; i < len(days); i++ {
+		if !days[i].Date.After(days[i-1].Date) {
+			t.Fatalf("days are not sorted at %s", days[i].Date)
+		}
+	}
+	if want := (731 + calendarChunkDays - 1) / calendarChunkDays; requests != want {
+		t.Errorf("got %d requests, want %d", requests, want)
+	}
+	if maxInFlight > calendarConcurrency {
+		t.Errorf("got %d concurrent requests, want at most %d", maxInFlight, calendarConcurrency)
+	}
+}
@@ -0,0 +1,350 @@
+// Package cassette records HTTP exchanges of an hrflow.Client to a file and replays them later. Credentials,
+// cookies, tokens and the username are redacted when recording, so recordings can be shared to reproduce problems without
+// sharing the account.
+//
+// Recording:
+//
+//	recorder := cassette.NewRecorder(nil)
+//	client.HttpClient.Transport = recorder
+//	// use the client
+//	err := recorder.Save("hrflow.json")
+//
+// Replaying:
+//
+//	replayer, err := cassette.Load("hrflow.json")
+//	client.HttpClient.Transport = replayer
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Redacted replaces sensitive values in recordings.
+const Redacted = "REDACTED"
+
+// Cassette is a recorded sequence of HTTP exchanges.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header"`
+	Body   string      `json:"body"`
+}
+
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// Recorder is an http.RoundTripper that records the exchanges done through Transport.
+type Recorder struct {
+	// Transport does the actual requests. If nil, http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+}
+
+// NewRecorder returns a recorder doing requests through transport, or http.DefaultTransport if transport is nil.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	return &Recorder{Transport: transport}
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	var requestBody []byte
+	if req.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, errors.Wrap(err, "reading request body")
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response body")
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Request: Request{
+			Method: req.Method,
+			URL:    redactURL(req.URL),
+			Header: redactHeader(req.Header),
+			Body:   redactForm(string(requestBody)),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     redactHeader(resp.Header),
+			Body:       redactResponse(string(responseBody)),
+		},
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// Cassette returns the exchanges recorded so far.
+func (r *Recorder) Cassette() Cassette {
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return Cassette{Interactions: append([]Interaction{}, r.cassette.Interactions...)}
+}
+
+// Save writes the recorded exchanges to path.
+func (r *Recorder) Save(path string) error {
+
+	// Recordings are mostly HTML and JSON, escaping it would make them hard to read.
+	var content bytes.Buffer
+	encoder := json.NewEncoder(&content)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(r.Cassette())
+	if err != nil {
+		return errors.Wrap(err, "encoding cassette")
+	}
+
+	err = ioutil.WriteFile(path, content.Bytes(), 0600)
+	if err != nil {
+		return errors.Wrap(err, "writing cassette")
+	}
+
+	return nil
+}
+
+// Replayer is an http.RoundTripper that responds with recorded responses instead of doing requests.
+// Requests are matched to the recorded ones by method and URL in the recorded order.
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer returns a replayer for the exchanges of c.
+func NewReplayer(c Cassette) *Replayer {
+	return &Replayer{
+		interactions: c.Interactions,
+		used:         make([]bool, len(c.Interactions)),
+	}
+}
+
+// Load reads a cassette saved by Recorder.Save and returns a replayer for it.
+func Load(path string) (*Replayer, error) {
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening cassette")
+	}
+	defer file.Close()
+
+	var c Cassette
+
+	err = json.NewDecoder(file).Decode(&c)
+	if err != nil {
+		return nil, errors.Wrap(err, "decoding cassette")
+	}
+
+	return NewReplayer(c), nil
+}
+
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	requestURL := redactURL(req.URL)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || interaction.Request.Method != req.Method || interaction.Request.URL != requestURL {
+			continue
+		}
+		r.used[i] = true
+
+		header := http.Header{}
+		for name, values := range interaction.Response.Header {
+			header[name] = append([]string{}, values...)
+		}
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response for %s %s", req.Method, requestURL)
+}
+
+// sensitiveFields are form fields and query parameters whose values are never recorded.
+var sensitiveFields = map[string]bool{
+	"username":          true,
+	"password":          true,
+	"samlresponse":      true,
+	"samlrequest":       true,
+	"wresult":           true,
+	"code":              true,
+	"id_token":          true,
+	"access_token":      true,
+	"state":             true,
+	"session_state":     true,
+	"client-request-id": true,
+}
+
+// sensitiveHeaders are headers whose values are never recorded.
+var sensitiveHeaders = []string{"Authorization", "Cookie", "X-Xsrf-Token"}
+
+func redactValues(values url.Values) url.Values {
+
+	redacted := url.Values{}
+	for name, v := range values {
+		if sensitiveFields[strings.ToLower(name)] {
+			redacted[name] = []string{Redacted}
+			continue
+		}
+		// Rows and requests are posted as JSON in form fields.
+		for _, value := range v {
+			redacted.Add(name, redactJSON(value))
+		}
+	}
+
+	return redacted
+}
+
+// usernameFieldRegex matches the JSON fields containing the username in employments, rows and requests.
+var usernameFieldRegex = regexp.MustCompile(`("(?:userName|modifiedBy|creator|emailReceiver)"\s*:\s*")(?:[^"\\]|\\.)*(")`)
+
+// redactJSON removes the username from JSON, or JavaScript containing it.
+func redactJSON(body string) string {
+	return usernameFieldRegex.ReplaceAllString(body, "${1}"+Redacted+"${2}")
+}
+
+func redactURL(u *url.URL) string {
+
+	redacted := *u
+	if len(u.RawQuery) != 0 {
+		redacted.RawQuery = redactValues(u.Query()).Encode()
+	}
+
+	return redacted.String()
+}
+
+func redactForm(body string) string {
+
+	values, err := url.ParseQuery(body)
+	if err != nil || len(values) == 0 {
+		return body
+	}
+
+	return redactValues(values).Encode()
+}
+
+var cookieValueRegex = regexp.MustCompile(`^([^=;]+)=[^;]*`)
+
+// urlHeaders are headers containing a URL, whose query can have the same values as the requests.
+var urlHeaders = []string{"Location", "Content-Location", "Referer"}
+
+func redactHeader(header http.Header) http.Header {
+
+	redacted := http.Header{}
+	for name, values := range header {
+		redacted[name] = append([]string{}, values...)
+	}
+	for _, name := range sensitiveHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, Redacted)
+		}
+	}
+	// Redirects of the login process carry the codes and tokens in the query.
+	for _, name := range urlHeaders {
+		for i, value := range redacted[name] {
+			u, err := url.Parse(value)
+			if err != nil {
+				redacted[name][i] = Redacted
+				continue
+			}
+			redacted[name][i] = redactURL(u)
+		}
+	}
+	// Cookie names and attributes are kept, so the replayed responses still set cookies.
+	for i, cookie := range redacted["Set-Cookie"] {
+		redacted["Set-Cookie"][i] = cookieValueRegex.ReplaceAllString(cookie, "${1}="+Redacted)
+	}
+
+	return redacted
+}
+
+// sensitiveInputs are the names of the form inputs whose values are never recorded in pages.
+const sensitiveInputs = `(?:UserName|Password|SAMLResponse|SAMLRequest|wresult|code|id_token|access_token|state|session_state)`
+
+var (
+	inputValueRegex  = regexp.MustCompile(`(?i)(<input[^>]*name="` + sensitiveInputs + `"[^>]*value=")[^"]*(")`)
+	valueInputRegex  = regexp.MustCompile(`(?i)(<input[^>]*value=")[^"]*("[^>]*name="` + sensitiveInputs + `")`)
+	queryValueRegex  = regexp.MustCompile(`(?i)([?&](?:amp;)?(?:username|password|samlresponse|samlrequest|wresult|code|id_token|access_token|state|session_state|client-request-id)=)[^&"'\s<>#]*`)
+	xsrfTokenRegex   = regexp.MustCompile(`(var RequestVerificationToken = ')[^']*(')`)
+	userRoleKeyRegex = regexp.MustCompile(`(var SELECTED_USER_AND_ROLEKEY = ")[^"]*(")`)
+)
+
+// redactResponse removes the credentials, the tokens of the login process and the username from HTML pages
+// and JSON responses.
+func redactResponse(body string) string {
+
+	body = inputValueRegex.ReplaceAllString(body, "${1}"+Redacted+"${2}")
+	body = valueInputRegex.ReplaceAllString(body, "${1}"+Redacted+"${2}")
+	// Links and scripts of the login process repeat the values of the redirects.
+	body = queryValueRegex.ReplaceAllString(body, "${1}"+Redacted)
+	body = xsrfTokenRegex.ReplaceAllString(body, "${1}"+Redacted+"${2}")
+	// The key contains the username, and is also a token of the session.
+	body = userRoleKeyRegex.ReplaceAllString(body, "${1}"+Redacted+"${2}")
+
+	return redactJSON(body)
+}
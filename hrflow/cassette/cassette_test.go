@@ -0,0 +1,208 @@
+package cassette
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// secrets are the values the stub server receives or sends that must not end up in recordings.
+var secrets = []string{"alice@corp.fi", "hunter2", "SECRETCODE", "SECRETSTATE", "SECRETCOOKIE", "SECRETTOKEN", "SECRETXSRF"}
+
+// newStubServer returns a server going through the steps of a login that failed once.
+func newStubServer() *httptest.Server {
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<form id="options" action="/login?client-request-id=SECRETSTATE">
+<input id="userNameInput" name="UserName" type="email" value="alice@corp.fi" />
+<input value="hunter2" name="Password" type="password" />
+<span id="errorText">Incorrect user ID or password.</span>
+</form>`))
+	})
+	mux.HandleFunc("/start", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "SECRETCOOKIE", Path: "/KirjaamoWeb"})
+		w.Header().Set("Location", "/cb?code=SECRETCODE&state=SECRETSTATE")
+		w.WriteHeader(http.StatusFound)
+		w.Write([]byte(`<a href="/cb?code=SECRETCODE&amp;state=SECRETSTATE">Found</a>`))
+	})
+	mux.HandleFunc("/cb", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<form><input type="hidden" name="id_token" value="SECRETTOKEN" /></form>
+<script>var RequestVerificationToken = 'SECRETXSRF';
+var SELECTED_USER_AND_ROLEKEY = "alice@corp.fi_EMPLOYEE";
+var employments = [{"userName":"alice@corp.fi","employmentID":1}];</script>`))
+	})
+
+	return httptest.NewServer(mux)
+}
+
+// login does the requests of a login that failed once through transport, and returns the last page.
+func login(t *testing.T, serverURL string, transport http.RoundTripper) string {
+
+	t.Helper()
+
+	client := &http.Client{Transport: transport}
+	form := url.Values{
+		"UserName": {"alice@corp.fi"},
+		"Password": {"hunter2"},
+		"row":      {`{"creator":"alice@corp.fi","hours":8}`},
+	}
+	resp, err := client.PostForm(serverURL+"/login", form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	req, err := http.NewRequest(http.MethodGet, serverURL+"/start?code=SECRETCODE", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Cookie", "session=SECRETCOOKIE")
+	req.Header.Set("X-XSRF-Token", "SECRETXSRF")
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(body)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+
+	server := newStubServer()
+	recorder := NewRecorder(nil)
+	login(t, server.URL, recorder)
+	server.Close()
+
+	dir, err := ioutil.TempDir("", "cassette")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "cassette.json")
+	err = recorder.Save(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range secrets {
+		if strings.Contains(string(content), secret) {
+			t.Errorf("recording contains %s", secret)
+		}
+	}
+	if interactions := len(recorder.Cassette().Interactions); interactions != 3 {
+		t.Errorf("got %d interactions, want 3", interactions)
+	}
+
+	// The redacted redirect is followed to the redacted callback, which the server was never asked.
+	replayer, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := login(t, server.URL, replayer)
+	if !strings.Contains(body, "var RequestVerificationToken = '"+Redacted+"'") {
+		t.Errorf("got replayed page %s", body)
+	}
+}
+
+func TestReplayWithoutRecording(t *testing.T) {
+
+	replayer := NewReplayer(Cassette{})
+	_, err := replayer.RoundTrip(httptest.NewRequest(http.MethodGet, "https://hrflow.example.com/?code=SECRETCODE", nil))
+	if err == nil || strings.Contains(err.Error(), "SECRETCODE") {
+		t.Errorf("got %v, want an error without the code", err)
+	}
+}
+
+func TestRedactForm(t *testing.T) {
+
+	got := redactForm("UserName=alice%40corp.fi&Password=hunter2&row=%7B%22modifiedBy%22%3A%22alice%40corp.fi%22%7D&hours=8")
+	values, err := url.ParseQuery(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"UserName": {Redacted},
+		"Password": {Redacted},
+		"row":      {`{"modifiedBy":"` + Redacted + `"}`},
+		"hours":    {"8"},
+	}
+	for name := range want {
+		if values.Get(name) != want.Get(name) {
+			t.Errorf("got %s=%s, want %s", name, values.Get(name), want.Get(name))
+		}
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+
+	header := http.Header{
+		"Authorization":    {"Bearer SECRETTOKEN"},
+		"Cookie":           {"session=SECRETCOOKIE"},
+		"X-Xsrf-Token":     {"SECRETXSRF"},
+		"Set-Cookie":       {"session=SECRETCOOKIE; Path=/KirjaamoWeb; HttpOnly"},
+		"Location":         {"https://hrflow.example.com/cb?code=SECRETCODE&state=SECRETSTATE"},
+		"Content-Location": {"/cb?id_token=SECRETTOKEN"},
+		"Content-Type":     {"text/html"},
+	}
+	got := redactHeader(header)
+
+	want := http.Header{
+		"Authorization":    {Redacted},
+		"Cookie":           {Redacted},
+		"X-Xsrf-Token":     {Redacted},
+		"Set-Cookie":       {"session=" + Redacted + "; Path=/KirjaamoWeb; HttpOnly"},
+		"Location":         {"https://hrflow.example.com/cb?code=" + Redacted + "&state=" + Redacted},
+		"Content-Location": {"/cb?id_token=" + Redacted},
+		"Content-Type":     {"text/html"},
+	}
+	for name := range want {
+		if got.Get(name) != want.Get(name) {
+			t.Errorf("got %s: %s, want %s", name, got.Get(name), want.Get(name))
+		}
+	}
+	if header.Get("Cookie") != "session=SECRETCOOKIE" {
+		t.Error("the original header was modified")
+	}
+}
+
+func TestRedactResponse(t *testing.T) {
+
+	content, err := ioutil.ReadFile(filepath.Join("..", "testdata", "login_error.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := redactResponse(string(content))
+	if strings.Contains(got, "user@example.com") {
+		t.Errorf("username was not redacted from %s", got)
+	}
+	if !strings.Contains(got, "Incorrect user ID or password") {
+		t.Errorf("the error was redacted from %s", got)
+	}
+
+	content, err = ioutil.ReadFile(filepath.Join("..", "testdata", "logged_in.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = redactResponse(string(content))
+	for _, secret := range []string{"xsrf-token-value", "u_EMPLOYEE"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("page contains %s after redacting", secret)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package hrflow
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// DayOff is a day off, or a range of them, that the calendar of HR Flow doesn't know about.
+type DayOff struct {
+	// Start and End are the first and the last day off, inclusive. End can be zero for a single day.
+	Start, End time.Time
+	// Yearly repeats the days off every year, the years of Start and End are ignored.
+	Yearly bool
+	// Description explains the day off.
+	Description string
+}
+
+// includes tells if date is one of the days off.
+func (d DayOff) includes(date time.Time) bool {
+
+	end := d.End
+	if end.IsZero() {
+		end = d.Start
+	}
+
+	if d.Yearly {
+		day := monthDay(date)
+		start, last := monthDay(d.Start), monthDay(end)
+		// A yearly range can continue to the next year, e.g. from 24.12. to 6.1.
+		if start <= last {
+			return start <= day && day <= last
+		}
+		return start <= day || day <= last
+	}
+
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	start := time.Date(d.Start.Year(), d.Start.Month(), d.Start.Day(), 0, 0, 0, 0, time.UTC)
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.UTC)
+
+	return !date.Before(start) && !date.After(end)
+}
+
+// monthDay returns the day of the year of t without leap days affecting it.
+func monthDay(t time.Time) int {
+	return int(t.Month())*100 + t.Day()
+}
+
+// DaysOff marks Days as non-workdays in the days from Source.
+type DaysOff struct {
+	Source CalendarSource
+	Days   []DayOff
+}
+
+// Calendar returns the days between startDate and endDate, inclusive, from Source with the days off merged.
+func (d *DaysOff) Calendar(startDate, endDate time.Time) ([]CalendarDay, error) {
+	return d.CalendarContext(context.Background(), startDate, endDate)
+}
+
+// CalendarContext is like Calendar but the requests to Source are bound to ctx.
+func (d *DaysOff) CalendarContext(ctx context.Context, startDate, endDate time.Time) ([]CalendarDay, error) {
+
+	days, err := d.Source.CalendarContext(ctx, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, day := range days {
+		for _, off := range d.Days {
+			if !off.includes(day.Date) {
+				continue
+			}
+			days[i].Workday = false
+			// The description of an actual holiday is more accurate.
+			if len(strings.TrimSpace(day.Description)) == 0 {
+				days[i].Description = off.Description
+			}
+			break
+		}
+	}
+
+	return days, nil
+}
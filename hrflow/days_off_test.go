@@ -0,0 +1,81 @@
+package hrflow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDayOffIncludes(t *testing.T) {
+
+	christmas := DayOff{Start: newDate(2000, time.December, 24), End: newDate(2000, time.January, 6), Yearly: true}
+	single := DayOff{Start: newDate(2026, time.December, 23)}
+	yearlySingle := DayOff{Start: newDate(2000, time.December, 23), Yearly: true}
+	summer := DayOff{Start: newDate(2026, time.July, 13), End: newDate(2026, time.July, 31)}
+
+	tests := []struct {
+		name string
+		off  DayOff
+		date time.Time
+		want bool
+	}{
+		{"yearly range start", christmas, newDate(2026, time.December, 24), true},
+		{"yearly range over new year", christmas, newDate(2027, time.January, 1), true},
+		{"yearly range end", christmas, newDate(2027, time.January, 6), true},
+		{"after yearly range", christmas, newDate(2027, time.January, 7), false},
+		{"before yearly range", christmas, newDate(2026, time.December, 23), false},
+		{"middle of the year", christmas, newDate(2026, time.June, 1), false},
+		{"single day", single, newDate(2026, time.December, 23), true},
+		{"single day with time", single, time.Date(2026, time.December, 23, 23, 59, 0, 0, time.Local), true},
+		{"single day another year", single, newDate(2027, time.December, 23), false},
+		{"day after single day", single, newDate(2026, time.December, 24), false},
+		{"yearly single day", yearlySingle, newDate(2031, time.December, 23), true},
+		{"day after yearly single day", yearlySingle, newDate(2031, time.December, 24), false},
+		{"range start", summer, newDate(2026, time.July, 13), true},
+		{"range end", summer, newDate(2026, time.July, 31), true},
+		{"range another year", summer, newDate(2027, time.July, 20), false},
+		{"after range", summer, newDate(2026, time.August, 1), false},
+		{"range in utc", summer, time.Date(2026, time.July, 31, 23, 0, 0, 0, time.UTC), true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.off.includes(test.date); got != test.want {
+				t.Errorf("got %v for %s, want %v", got, test.date, test.want)
+			}
+		})
+	}
+}
+
+func TestDaysOff(t *testing.T) {
+
+	source := &DaysOff{
+		Source: FinnishCalendar{},
+		Days: []DayOff{
+			{Start: newDate(2000, time.December, 22), End: newDate(2000, time.December, 24), Yearly: true, Description: "Company holiday"},
+		},
+	}
+	days, err := source.Calendar(newDate(2026, time.December, 21), newDate(2026, time.December, 24))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		workday     bool
+		description string
+	}{
+		{true, ""},
+		{false, "Company holiday"},
+		{false, "Company holiday"},
+		// Holidays keep their own description.
+		{false, "Christmas Eve"},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, day := range days {
+		if day.Workday != want[i].workday || day.Description != want[i].description {
+			t.Errorf("got %v %q on %s, want %v %q", day.Workday, day.Description, day.Date.Format("2.1."),
+				want[i].workday, want[i].description)
+		}
+	}
+}
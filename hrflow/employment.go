@@ -1,5 +1,12 @@
 package hrflow
 
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
 type Employment struct {
 	IsPassive           bool  `json:"isPassive"`
 	IsDefaultEmployment bool  `json:"isDefaultEmployment"`
@@ -22,3 +29,72 @@ type Employment struct {
 	ValueSettings          string   `json:"valueSettings"`
 	OrganizationPositionID int64    `json:"organizationPositionId"`
 }
+
+// employmentDateFormats are the formats tried when parsing employment start and end dates.
+var employmentDateFormats = []string{"2006-01-02T15:04:05", hrFlowTimeFormat, "2006-01-02", hrFlowDateFormat}
+
+func parseEmploymentDate(s string) (time.Time, error) {
+
+	for _, format := range employmentDateFormats {
+		date, err := time.ParseInLocation(format, s, time.Local)
+		if err == nil {
+			return date, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unknown date format %s", s)
+}
+
+// Active tells if the employment is not passive and date is between its start and end dates.
+// Dates that can't be parsed are treated as unlimited.
+func (e Employment) Active(date time.Time) bool {
+
+	if e.IsPassive {
+		return false
+	}
+
+	if start, err := parseEmploymentDate(e.StartDate); err == nil && date.Before(start) {
+		return false
+	}
+	if e.EndDate != nil && len(*e.EndDate) != 0 {
+		// End date is the last day of the employment.
+		if end, err := parseEmploymentDate(*e.EndDate); err == nil && !date.Before(end.AddDate(0, 0, 1)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Employment returns the employment to report to on date. If EmploymentID is set, the employment with that ID is returned.
+// Otherwise the default employment is preferred among the active ones.
+func (c *Client) Employment(date time.Time) (Employment, error) {
+
+	if c.EmploymentID != 0 {
+		for _, employment := range c.Employments() {
+			if employment.EmploymentID == c.EmploymentID {
+				return employment, nil
+			}
+		}
+		return Employment{}, errors.Wrapf(ErrNoEmployment, "employment %d", c.EmploymentID)
+	}
+
+	var active []Employment
+	for _, employment := range c.Employments() {
+		if employment.Active(date) {
+			active = append(active, employment)
+		}
+	}
+
+	if len(active) == 0 {
+		return Employment{}, errors.Wrap(ErrNoEmployment, "no active employment")
+	}
+
+	for _, employment := range active {
+		if employment.IsDefaultEmployment {
+			return employment, nil
+		}
+	}
+
+	return active[0], nil
+}
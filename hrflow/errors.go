@@ -0,0 +1,33 @@
+package hrflow
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidCredentials is returned when the login is rejected because of a wrong username or password.
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	// ErrUnexpectedPage is returned when a page of the login process doesn't look like expected, usually because the service has changed.
+	ErrUnexpectedPage = errors.New("unexpected page in login process")
+	// ErrSessionExpired is returned when the backend no longer accepts the session and the client has to authenticate again.
+	ErrSessionExpired = errors.New("session expired")
+	// ErrNoEmployment is returned when there is no employment to report to.
+	ErrNoEmployment = errors.New("no employment found")
+)
+
+// ValidationError is returned when the backend refuses to save a change, for example because of overlapping rows.
+type ValidationError struct {
+	// Messages are the reasons given by the backend, if any.
+	Messages []string
+}
+
+func (e *ValidationError) Error() string {
+
+	if len(e.Messages) == 0 {
+		return "backend returned unsuccessful status"
+	}
+
+	return "backend validation failed: " + strings.Join(e.Messages, ", ")
+}
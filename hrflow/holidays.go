@@ -0,0 +1,106 @@
+package hrflow
+
+import (
+	"context"
+	"time"
+)
+
+// FinnishCalendar calculates the calendar from the Finnish public holidays without contacting HR Flow. Midsummer Eve
+// and Christmas Eve aren't public holidays but are conventionally days off, so they are included as well. Company
+// specific days off are not known.
+type FinnishCalendar struct{}
+
+// Calendar returns the days between startDate and endDate, inclusive, sorted by date.
+func (FinnishCalendar) Calendar(startDate, endDate time.Time) ([]CalendarDay, error) {
+	return FinnishCalendar{}.CalendarContext(context.Background(), startDate, endDate)
+}
+
+// CalendarContext is like Calendar. It implements CalendarSource, ctx is not used.
+func (FinnishCalendar) CalendarContext(ctx context.Context, startDate, endDate time.Time) ([]CalendarDay, error) {
+
+	startDate = time.Date(startDate.Year(), startDate.Month(), startDate.Day(), 0, 0, 0, 0, startDate.Location())
+	endDate = time.Date(endDate.Year(), endDate.Month(), endDate.Day(), 0, 0, 0, 0, endDate.Location())
+
+	days := []CalendarDay{}
+	for date := startDate; !date.After(endDate); date = date.AddDate(0, 0, 1) {
+		description, holiday := FinnishHoliday(date)
+		weekday := date.Weekday()
+		days = append(days, CalendarDay{
+			Date:        date,
+			Workday:     !holiday && weekday != time.Saturday && weekday != time.Sunday,
+			HolidayCalc: !holiday && weekday != time.Sunday,
+			Description: description,
+			Weekday:     weekday,
+		})
+	}
+
+	return days, nil
+}
+
+// FinnishHoliday returns the name of the holiday on date, and if date is a holiday at all.
+func FinnishHoliday(date time.Time) (string, bool) {
+
+	year, month, day := date.Date()
+	// Comparing dates in UTC avoids daylight saving time shifting the days between the date and Easter.
+	date = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	easter := Easter(year)
+
+	switch {
+	case month == time.January && day == 1:
+		return "New Year's Day", true
+	case month == time.January && day == 6:
+		return "Epiphany", true
+	case date.Equal(easter.AddDate(0, 0, -2)):
+		return "Good Friday", true
+	case date.Equal(easter.AddDate(0, 0, -1)):
+		return "Holy Saturday", true
+	case date.Equal(easter):
+		return "Easter Sunday", true
+	case date.Equal(easter.AddDate(0, 0, 1)):
+		return "Easter Monday", true
+	case month == time.May && day == 1:
+		return "May Day", true
+	case date.Equal(easter.AddDate(0, 0, 39)):
+		return "Ascension Day", true
+	case date.Equal(easter.AddDate(0, 0, 49)):
+		return "Whitsunday", true
+	case month == time.June && day >= 19 && day <= 25 && date.Weekday() == time.Friday:
+		return "Midsummer Eve", true
+	case month == time.June && day >= 20 && day <= 26 && date.Weekday() == time.Saturday:
+		return "Midsummer Day", true
+	case date.Weekday() == time.Saturday &&
+		(month == time.October && day == 31 || month == time.November && day <= 6):
+		return "All Saints' Day", true
+	case month == time.December && day == 6:
+		return "Independence Day", true
+	case month == time.December && day == 24:
+		return "Christmas Eve", true
+	case month == time.December && day == 25:
+		return "Christmas Day", true
+	case month == time.December && day == 26:
+		return "St. Stephen's Day", true
+	}
+
+	return "", false
+}
+
+// Easter returns the date of Easter Sunday in year, in UTC. It uses the anonymous Gregorian algorithm.
+func Easter(year int) time.Time {
+
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,134 @@
+package hrflow
+
+import (
+	"testing"
+	"time"
+)
+
+// newDate returns the midnight of the date in the local time.
+func newDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestEaster(t *testing.T) {
+
+	tests := []struct {
+		year  int
+		month time.Month
+		day   int
+	}{
+		{1818, time.March, 22},
+		{1943, time.April, 25},
+		{2000, time.April, 23},
+		{2008, time.March, 23},
+		{2019, time.April, 21},
+		{2024, time.March, 31},
+		{2025, time.April, 20},
+		{2026, time.April, 5},
+		{2027, time.March, 28},
+		{2038, time.April, 25},
+	}
+
+	for _, test := range tests {
+		want := time.Date(test.year, test.month, test.day, 0, 0, 0, 0, time.UTC)
+		if got := Easter(test.year); !got.Equal(want) {
+			t.Errorf("got Easter %s in %d, want %s", got.Format("2.1."), test.year, want.Format("2.1."))
+		}
+	}
+}
+
+func TestFinnishHoliday(t *testing.T) {
+
+	// The moving holidays of each year, the fixed ones are the same every year.
+	tests := []struct {
+		year   int
+		moving map[string]time.Time
+	}{
+		{2024, map[string]time.Time{
+			"Good Friday":     newDate(2024, time.March, 29),
+			"Holy Saturday":   newDate(2024, time.March, 30),
+			"Easter Sunday":   newDate(2024, time.March, 31),
+			"Easter Monday":   newDate(2024, time.April, 1),
+			"Ascension Day":   newDate(2024, time.May, 9),
+			"Whitsunday":      newDate(2024, time.May, 19),
+			"Midsummer Eve":   newDate(2024, time.June, 21),
+			"Midsummer Day":   newDate(2024, time.June, 22),
+			"All Saints' Day": newDate(2024, time.November, 2),
+		}},
+		{2025, map[string]time.Time{
+			"Good Friday":     newDate(2025, time.April, 18),
+			"Holy Saturday":   newDate(2025, time.April, 19),
+			"Easter Sunday":   newDate(2025, time.April, 20),
+			"Easter Monday":   newDate(2025, time.April, 21),
+			"Ascension Day":   newDate(2025, time.May, 29),
+			"Whitsunday":      newDate(2025, time.June, 8),
+			"Midsummer Eve":   newDate(2025, time.June, 20),
+			"Midsummer Day":   newDate(2025, time.June, 21),
+			"All Saints' Day": newDate(2025, time.November, 1),
+		}},
+		{2026, map[string]time.Time{
+			"Good Friday":     newDate(2026, time.April, 3),
+			"Holy Saturday":   newDate(2026, time.April, 4),
+			"Easter Sunday":   newDate(2026, time.April, 5),
+			"Easter Monday":   newDate(2026, time.April, 6),
+			"Ascension Day":   newDate(2026, time.May, 14),
+			"Whitsunday":      newDate(2026, time.May, 24),
+			"Midsummer Eve":   newDate(2026, time.June, 19),
+			"Midsummer Day":   newDate(2026, time.June, 20),
+			"All Saints' Day": newDate(2026, time.October, 31),
+		}},
+		{2027, map[string]time.Time{
+			"Good Friday":     newDate(2027, time.March, 26),
+			"Holy Saturday":   newDate(2027, time.March, 27),
+			"Easter Sunday":   newDate(2027, time.March, 28),
+			"Easter Monday":   newDate(2027, time.March, 29),
+			"Ascension Day":   newDate(2027, time.May, 6),
+			"Whitsunday":      newDate(2027, time.May, 16),
+			"Midsummer Eve":   newDate(2027, time.June, 25),
+			"Midsummer Day":   newDate(2027, time.June, 26),
+			"All Saints' Day": newDate(2027, time.November, 6),
+		}},
+	}
+
+	for _, test := range tests {
+		want := map[string]string{
+			dateKey(newDate(test.year, time.January, 1)):   "New Year's Day",
+			dateKey(newDate(test.year, time.January, 6)):   "Epiphany",
+			dateKey(newDate(test.year, time.May, 1)):       "May Day",
+			dateKey(newDate(test.year, time.December, 6)):  "Independence Day",
+			dateKey(newDate(test.year, time.December, 24)): "Christmas Eve",
+			dateKey(newDate(test.year, time.December, 25)): "Christmas Day",
+			dateKey(newDate(test.year, time.December, 26)): "St. Stephen's Day",
+		}
+		for name, date := range test.moving {
+			want[dateKey(date)] = name
+		}
+
+		// Every day of the year is checked, so holidays on wrong days are caught too.
+		for date := newDate(test.year, time.January, 1); date.Year() == test.year; date = date.AddDate(0, 0, 1) {
+			name, holiday := FinnishHoliday(date)
+			if holiday != (want[dateKey(date)] != "") || name != want[dateKey(date)] {
+				t.Errorf("got %q, %v on %s, want %q", name, holiday, date.Format("2.1.2006"), want[dateKey(date)])
+			}
+		}
+	}
+}
+
+func TestFinnishCalendar(t *testing.T) {
+
+	days, err := FinnishCalendar{}.Calendar(newDate(2026, time.April, 2), newDate(2026, time.April, 7))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Thursday before Easter to the Tuesday after it.
+	want := []bool{true, false, false, false, false, true}
+	if len(days) != len(want) {
+		t.Fatalf("got %d days, want %d", len(days), len(want))
+	}
+	for i, day := range days {
+		if day.Workday != want[i] {
+			t.Errorf("got workday %v on %s, want %v", day.Workday, day.Date.Format("2.1."), want[i])
+		}
+	}
+}
@@ -1,8 +1,19 @@
 package hrflow
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -10,25 +21,196 @@ const (
 	hrFlowDateFormat = "02.01.2006"
 )
 
+const (
+	// DefaultBaseURL is the address of the production service.
+	DefaultBaseURL = "https://hrflow.accountor.fi"
+	// DefaultLoginPath is the path of the login page for employees.
+	DefaultLoginPath = "/KirjaamoWeb/login/Employee"
+)
+
+// Paths of the endpoints used by the client, relative to the base URL.
+const (
+	CalendarPath         = "/KirjaamoWeb/calendar/GetCalendar"
+	WorkLogRowsPath      = "/KirjaamoWeb/employee/GetWorkLogRows"
+	NewWorkLogRowPath    = "/KirjaamoWeb/employee/NewWorkLogRow"
+	DeleteWorkLogRowPath = "/KirjaamoWeb/employee/DeleteWorkLogRow"
+	ListValuesPath       = "/KirjaamoWeb/employee/GetListValues"
+)
+
 type Client struct {
 	username string
 	password string
 
+	baseURL   string
+	loginPath string
+	timeout   time.Duration
+	clock     func() time.Time
+	logger    *log.Logger
+
+	// authMu makes sure only one authentication runs at a time.
+	authMu sync.Mutex
+	// sessionMu guards the session values, generation is incremented every time they change.
+	sessionMu   sync.RWMutex
+	generation  uint64
 	xsrfToken   string
 	userRoleKey string
-	Employments []Employment
+	employments []Employment
+	// EmploymentID selects the employment used for reporting. If zero, one is chosen automatically, see Employment.
+	EmploymentID int64
 
 	HttpClient *http.Client
 }
 
-func NewClient(username, password string) *Client {
+// NewClient creates a client for the user, configured with options. Call Authenticate before using the client.
+func NewClient(username, password string, options ...Option) *Client {
 
 	cookieJar, _ := cookiejar.New(nil)
-	return &Client{
-		username: username,
-		password: password,
+	c := &Client{
+		username:  username,
+		password:  password,
+		baseURL:   DefaultBaseURL,
+		loginPath: DefaultLoginPath,
+		clock:     time.Now,
+		logger:    log.New(ioutil.Discard, "", 0),
 		HttpClient: &http.Client{
 			Jar: cookieJar,
 		},
 	}
+	for _, option := range options {
+		option(c)
+	}
+	// Applied last so the order of WithTimeout and WithHTTPClient doesn't matter.
+	if c.timeout != 0 {
+		c.HttpClient.Timeout = c.timeout
+	}
+
+	return c
+}
+
+// now returns the current time from the clock of the client.
+func (c *Client) now() time.Time {
+	return c.clock()
+}
+
+// endpoint returns the absolute URL of path.
+func (c *Client) endpoint(path string) string {
+	return c.baseURL + path
+}
+
+// postForm posts the form encoded body to endpoint and decodes the JSON response into v.
+func (c *Client) postForm(ctx context.Context, endpoint string, body url.Values, v interface{}) error {
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(body.Encode()))
+	if err != nil {
+		return errors.Wrap(err, "creating request")
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	return c.doJSON(req, v)
+}
+
+// doJSON does an authenticated request and decodes the JSON response into v. If the backend rejects the session,
+// the client authenticates again and retries the request once.
+func (c *Client) doJSON(req *http.Request, v interface{}) error {
+
+	token, generation := c.session()
+	err := c.doJSONWithToken(req, token, v)
+	if err != ErrSessionExpired {
+		return err
+	}
+
+	c.logger.Printf("session expired on %s %s, authenticating again", req.Method, req.URL)
+	err = c.reauthenticate(req.Context(), generation)
+	if err != nil {
+		return errors.Wrap(err, "authenticating expired session")
+	}
+
+	retry := req.Clone(req.Context())
+	// The client added the old session cookies to the request, the new ones are added from the jar.
+	retry.Header.Del("Cookie")
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return errors.Wrap(err, "rewinding request body")
+		}
+	}
+	token, _ = c.session()
+
+	return c.doJSONWithToken(retry, token, v)
+}
+
+// doJSONWithToken does a single request with the XSRF token. ErrSessionExpired is returned if the backend rejects the session.
+func (c *Client) doJSONWithToken(req *http.Request, token string, v interface{}) error {
+
+	req.Header.Set("X-XSRF-TOKEN", token)
+	req.Header.Set("Accept", "application/json")
+	c.logger.Printf("%s %s", req.Method, req.URL)
+	resp, err := c.HttpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "doing request")
+	}
+	defer resp.Body.Close()
+
+	if sessionRejected(req, resp) {
+		return ErrSessionExpired
+	}
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("http status error %d %s", resp.StatusCode, resp.Status)
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(v)
+	if err != nil {
+		return errors.Wrap(err, "decoding response")
+	}
+
+	return nil
+}
+
+// session returns the current XSRF token and the generation of the session.
+func (c *Client) session() (string, uint64) {
+
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+
+	return c.xsrfToken, c.generation
+}
+
+// setSession replaces the session values.
+func (c *Client) setSession(xsrfToken, userRoleKey string, employments []Employment) {
+
+	c.sessionMu.Lock()
+	defer c.sessionMu.Unlock()
+
+	c.generation++
+	c.xsrfToken = xsrfToken
+	c.userRoleKey = userRoleKey
+	c.employments = employments
+}
+
+// Employments returns the employments of the current session. It is safe to call while other goroutines use
+// the client, even if they authenticate again.
+//
+// Employments replaces the Employments field of earlier versions, which the client overwrote while it was
+// being read when the session was renewed. Replace client.Employments with client.Employments().
+func (c *Client) Employments() []Employment {
+
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+
+	return append([]Employment{}, c.employments...)
+}
+
+// sessionRejected tells if the response means the session is no longer valid. Depending on the endpoint the
+// backend either responds with 401 or 403, or redirects to the login page which responds with HTML instead of JSON.
+func sessionRejected(req *http.Request, resp *http.Response) bool {
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return true
+	}
+	if resp.Request != nil && resp.Request.URL.String() != req.URL.String() {
+		return true
+	}
+
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/html")
 }
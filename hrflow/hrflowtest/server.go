@@ -0,0 +1,635 @@
+// Package hrflowtest provides an in-process fake HR Flow service for testing code that uses hrflow.Client.
+package hrflowtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/myyra/hrflow/hrflow"
+)
+
+const (
+	timeFormat = "2006-01-02 15:04:05.000"
+	dateFormat = "02.01.2006"
+
+	credentialsPath = "/adfs/ls"
+	authPath        = "/adfs/auth"
+	oidcPath        = "/KirjaamoWeb/signin-oidc"
+	sessionCookie   = "HRFlowSession"
+)
+
+// Server is a fake HR Flow service. It emulates the login process, the calendar and the work log endpoints,
+// storing rows in memory. Create one with NewServer and point a client to it with Client or hrflow.WithBaseURL.
+type Server struct {
+	*httptest.Server
+
+	// Username and Password are the only accepted credentials.
+	Username string
+	Password string
+
+	mu          sync.Mutex
+	employments []hrflow.Employment
+	holidays    map[string]string
+	lists       map[string][]hrflow.ListItem
+	rows        map[int64]hrflow.WorkLogRow
+	nextID      int64
+	// codes are the one time codes of logins in progress.
+	codes map[string]bool
+	// sessions maps session cookies to their XSRF tokens.
+	sessions          map[string]string
+	failNextSave      []string
+	failNextSaveIsSet bool
+}
+
+// NewServer starts a fake service accepting the given credentials. It has one default employment and no holidays.
+// Close the server when done.
+func NewServer(username, password string) *Server {
+
+	s := &Server{
+		Username: username,
+		Password: password,
+		employments: []hrflow.Employment{{
+			IsDefaultEmployment: true,
+			EmploymentID:        1,
+			PersonID:            1,
+			Name:                "1 Test Employee",
+			ListName:            "Employee Test",
+			GroupID:             1,
+			CustomerID:          1,
+			UserName:            username,
+			StartDate:           "2000-01-01T00:00:00",
+			EnterpriseName:      "Test Company Oy",
+		}},
+		holidays: map[string]string{},
+		lists:    map[string][]hrflow.ListItem{},
+		rows:     map[int64]hrflow.WorkLogRow{},
+		nextID:   1,
+		codes:    map[string]bool{},
+		sessions: map[string]string{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(hrflow.DefaultLoginPath, s.handleLoginPage)
+	mux.HandleFunc(credentialsPath, s.handleCredentials)
+	mux.HandleFunc(authPath, s.handleAuth)
+	mux.HandleFunc(oidcPath, s.handleOIDC)
+	mux.HandleFunc(hrflow.CalendarPath, s.authorized(s.handleCalendar))
+	mux.HandleFunc(hrflow.WorkLogRowsPath, s.authorized(s.handleWorkLogRows))
+	mux.HandleFunc(hrflow.NewWorkLogRowPath, s.authorized(s.handleNewWorkLogRow))
+	mux.HandleFunc(hrflow.DeleteWorkLogRowPath, s.authorized(s.handleDeleteWorkLogRow))
+	mux.HandleFunc(hrflow.ListValuesPath, s.authorized(s.handleListValues))
+	s.Server = httptest.NewServer(mux)
+
+	return s
+}
+
+// Client returns a client using the server with the accepted credentials.
+func (s *Server) Client(options ...hrflow.Option) *hrflow.Client {
+	return hrflow.NewClient(s.Username, s.Password, append([]hrflow.Option{hrflow.WithBaseURL(s.URL)}, options...)...)
+}
+
+// SetEmployments replaces the employments returned when logging in.
+func (s *Server) SetEmployments(employments []hrflow.Employment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.employments = employments
+}
+
+// AddHoliday makes date a non-workday with description in the calendar. Weekends are non-workdays by default.
+func (s *Server) AddHoliday(date time.Time, description string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.holidays[date.Format(dateFormat)] = description
+}
+
+// SetListItems replaces the items of a dimension list such as hrflow.ListProjects.
+func (s *Server) SetListItems(listID string, items []hrflow.ListItem) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[listID] = items
+}
+
+// AddRow stores a row as if it had been reported, and returns it with its assigned ID.
+func (s *Server) AddRow(row hrflow.WorkLogRow) hrflow.WorkLogRow {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addRow(row)
+}
+
+// Rows returns the stored rows sorted by start time.
+func (s *Server) Rows() []hrflow.WorkLogRow {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []hrflow.WorkLogRow{}
+	for _, row := range s.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i].StartTime < rows[j].StartTime
+	})
+
+	return rows
+}
+
+// SetRowStatus changes the status of a stored row, e.g. to approve it.
+func (s *Server) SetRowStatus(id int64, status string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if row, ok := s.rows[id]; ok {
+		row.Status = status
+		s.rows[id] = row
+	}
+}
+
+// FailNextSave makes the next create, update or delete fail validation with messages.
+func (s *Server) FailNextSave(messages ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNextSave = messages
+	s.failNextSaveIsSet = true
+}
+
+// ExpireSessions invalidates all sessions, so clients have to log in again.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sessions = map[string]string{}
+}
+
+var loginPageTemplate = template.Must(template.New("login").Parse(`<!DOCTYPE html>
+<html>
+<body>
+<form id="options" method="post" action="{{.Action}}">
+{{if .Error}}<span id="errorText">{{.Error}}</span>{{end}}
+<input id="userNameInput" name="UserName" type="email" value="">
+<input id="passwordInput" name="Password" type="password">
+<input id="authMethod" type="hidden" name="AuthMethod" value="FormsAuthentication">
+<span id="submitButton" class="submit" role="button">Sign in</span>
+</form>
+</body>
+</html>
+`))
+
+var autoPostTemplate = template.Must(template.New("autopost").Parse(`<!DOCTYPE html>
+<html>
+<body onload="document.forms[0].submit()">
+<form method="POST" name="hiddenform" action="{{.Action}}">
+{{range $name, $value := .Values}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<noscript><input type="submit" value="Submit"></noscript>
+</form>
+</body>
+</html>
+`))
+
+var loggedInTemplate = template.Must(template.New("loggedin").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<script>
+var RequestVerificationToken = '{{.Token}}';
+var SELECTED_USER_AND_ROLEKEY = "{{.UserRoleKey}}";
+var employments = {{.Employments}};
+</script>
+</head>
+<body></body>
+</html>
+`))
+
+func (s *Server) handleLoginPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	loginPageTemplate.Execute(w, map[string]string{"Action": credentialsPath})
+}
+
+func (s *Server) handleCredentials(w http.ResponseWriter, r *http.Request) {
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if r.PostFormValue("UserName") != s.Username || r.PostFormValue("Password") != s.Password {
+		loginPageTemplate.Execute(w, map[string]string{"Action": credentialsPath, "Error": "Incorrect user ID or password."})
+		return
+	}
+
+	autoPostTemplate.Execute(w, map[string]interface{}{
+		"Action": authPath,
+		"Values": map[string]string{"SAMLResponse": s.newCode()},
+	})
+}
+
+func (s *Server) handleAuth(w http.ResponseWriter, r *http.Request) {
+
+	if !s.useCode(r.PostFormValue("SAMLResponse")) {
+		http.Error(w, "invalid SAML response", http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	autoPostTemplate.Execute(w, map[string]interface{}{
+		"Action": oidcPath,
+		"Values": map[string]string{"code": s.newCode(), "state": "state"},
+	})
+}
+
+func (s *Server) handleOIDC(w http.ResponseWriter, r *http.Request) {
+
+	if !s.useCode(r.PostFormValue("code")) {
+		http.Error(w, "invalid code", http.StatusBadRequest)
+		return
+	}
+
+	session := randomString()
+	token := randomString()
+
+	s.mu.Lock()
+	s.sessions[session] = token
+	employments, _ := json.Marshal(s.employments)
+	s.mu.Unlock()
+
+	// The cookie is scoped to the application like the real service does.
+	http.SetCookie(w, &http.Cookie{Name: sessionCookie, Value: session, Path: "/KirjaamoWeb", HttpOnly: true})
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	loggedInTemplate.Execute(w, map[string]interface{}{
+		"Token":       token,
+		"UserRoleKey": s.Username + "_EMPLOYEE",
+		// The employments are written to the script as is, like the real service does.
+		"Employments": template.JS(employments),
+	})
+}
+
+// authorized only lets requests with a valid session cookie and XSRF token through.
+// Others are redirected to the login page like the real service does.
+func (s *Server) authorized(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		var token string
+		var ok bool
+		if cookie, err := r.Cookie(sessionCookie); err == nil {
+			s.mu.Lock()
+			token, ok = s.sessions[cookie.Value]
+			s.mu.Unlock()
+		}
+		if !ok {
+			http.Redirect(w, r, hrflow.DefaultLoginPath, http.StatusFound)
+			return
+		}
+		if r.Header.Get("X-XSRF-TOKEN") != token {
+			http.Error(w, "invalid xsrf token", http.StatusForbidden)
+			return
+		}
+
+		handler(w, r)
+	}
+}
+
+// calendarDay is the JSON format of a calendar day.
+type calendarDay struct {
+	TES         string `json:"tes"`
+	ID          int    `json:"id"`
+	Date        string `json:"date"`
+	DateType    string `json:"dateType"`
+	Workday     bool   `json:"workDay"`
+	Holiday     bool   `json:"holiday"`
+	Description string `json:"description"`
+	Weekday     string `json:"weekDay"`
+}
+
+func (s *Server) handleCalendar(w http.ResponseWriter, r *http.Request) {
+
+	// The parameters are accepted both in the query and in the body.
+	params := r.URL.Query()
+	if len(params) == 0 {
+		body, _ := ioutil.ReadAll(r.Body)
+		params, _ = url.ParseQuery(string(body))
+	}
+	start, err := time.ParseInLocation(dateFormat, params.Get("startDate"), time.UTC)
+	if err != nil {
+		http.Error(w, "invalid startDate", http.StatusBadRequest)
+		return
+	}
+	end, err := time.ParseInLocation(dateFormat, params.Get("endDate"), time.UTC)
+	if err != nil {
+		http.Error(w, "invalid endDate", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	days := []calendarDay{}
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		weekday := int(date.Weekday())
+		if weekday == 0 {
+			weekday = 7
+		}
+		description, holiday := s.holidays[date.Format(dateFormat)]
+		if !holiday {
+			description = " "
+		}
+		days = append(days, calendarDay{
+			ID:          int(date.Unix() / 86400),
+			Date:        date.Format("2006-01-02T15:04:05"),
+			Workday:     weekday < 6 && !holiday,
+			Holiday:     weekday < 7 && !holiday,
+			Description: description,
+			Weekday:     strconv.Itoa(weekday),
+		})
+	}
+
+	writeJSON(w, days)
+}
+
+func (s *Server) handleWorkLogRows(w http.ResponseWriter, r *http.Request) {
+
+	var request hrflow.WorkLogRequest
+	err := json.Unmarshal([]byte(r.PostFormValue("workLogRequest")), &request)
+	if err != nil {
+		http.Error(w, "invalid workLogRequest", http.StatusBadRequest)
+		return
+	}
+	start, err := time.ParseInLocation(dateFormat, request.StartDate, time.Local)
+	if err != nil {
+		http.Error(w, "invalid startDate", http.StatusBadRequest)
+		return
+	}
+	end, err := time.ParseInLocation(dateFormat, request.EndDate, time.Local)
+	if err != nil {
+		http.Error(w, "invalid endDate", http.StatusBadRequest)
+		return
+	}
+	statuses := map[string]bool{}
+	for _, status := range request.StatusList {
+		statuses[status] = true
+	}
+
+	rows := []hrflow.WorkLogRow{}
+	for _, row := range s.Rows() {
+		day, err := row.Day()
+		if err != nil || day.Before(start) || day.After(end) || !statuses[row.Status] {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	writeJSON(w, rows)
+}
+
+// handleNewWorkLogRow saves new rows, or updates an existing row if the request has the update flag.
+func (s *Server) handleNewWorkLogRow(w http.ResponseWriter, r *http.Request) {
+
+	var row hrflow.WorkLogRow
+	err := json.Unmarshal([]byte(r.PostFormValue("workLogRow")), &row)
+	if err != nil {
+		http.Error(w, "invalid workLogRow", http.StatusBadRequest)
+		return
+	}
+	var request hrflow.WorkLogRequest
+	err = json.Unmarshal([]byte(r.PostFormValue("workLogRequest")), &request)
+	if err != nil {
+		http.Error(w, "invalid workLogRequest", http.StatusBadRequest)
+		return
+	}
+	if request.IsUpdateRow {
+		s.updateRow(w, row)
+		return
+	}
+	var copyToDates []string
+	err = json.Unmarshal([]byte(r.PostFormValue("copyToDates")), &copyToDates)
+	if err != nil {
+		http.Error(w, "invalid copyToDates", http.StatusBadRequest)
+		return
+	}
+
+	rows := []hrflow.WorkLogRow{row}
+	for _, d := range copyToDates {
+		date, err := time.ParseInLocation(timeFormat, d, time.Local)
+		if err != nil {
+			http.Error(w, "invalid copy date", http.StatusBadRequest)
+			return
+		}
+		rows = append(rows, copyRow(row, date))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failSave(w) {
+		return
+	}
+	for i, row := range rows {
+		// Copies to duplicate or overlapping dates are refused like overlaps with stored rows.
+		if messages := s.validate(row, rows[:i]...); len(messages) != 0 {
+			writeJSON(w, response{Messages: messages})
+			return
+		}
+	}
+	for _, row := range rows {
+		row.Status = hrflow.StatusNew
+		s.addRow(row)
+	}
+
+	writeJSON(w, response{ActionSuccessful: true})
+}
+
+// updateRow replaces a stored row with row.
+func (s *Server) updateRow(w http.ResponseWriter, row hrflow.WorkLogRow) {
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failSave(w) {
+		return
+	}
+	old, ok := s.rows[row.Id]
+	if !ok {
+		writeJSON(w, response{Messages: []string{fmt.Sprintf("row %d not found", row.Id)}})
+		return
+	}
+	if old.Status == hrflow.StatusApproved {
+		writeJSON(w, response{Messages: []string{"row is already approved"}})
+		return
+	}
+	if messages := s.validate(row); len(messages) != 0 {
+		writeJSON(w, response{Messages: messages})
+		return
+	}
+	row.Status = old.Status
+	s.rows[row.Id] = row
+
+	writeJSON(w, response{ActionSuccessful: true})
+}
+
+func (s *Server) handleDeleteWorkLogRow(w http.ResponseWriter, r *http.Request) {
+
+	id, err := strconv.ParseInt(r.PostFormValue("workLogRowId"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid workLogRowId", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.failSave(w) {
+		return
+	}
+	row, ok := s.rows[id]
+	if !ok {
+		writeJSON(w, response{Messages: []string{fmt.Sprintf("row %d not found", id)}})
+		return
+	}
+	if row.Status == hrflow.StatusApproved {
+		writeJSON(w, response{Messages: []string{"row is already approved"}})
+		return
+	}
+	delete(s.rows, id)
+
+	writeJSON(w, response{ActionSuccessful: true})
+}
+
+func (s *Server) handleListValues(w http.ResponseWriter, r *http.Request) {
+
+	s.mu.Lock()
+	items := s.lists[r.PostFormValue("listId")]
+	s.mu.Unlock()
+
+	if items == nil {
+		items = []hrflow.ListItem{}
+	}
+
+	writeJSON(w, items)
+}
+
+// response is the JSON format of save responses.
+type response struct {
+	ActionSuccessful bool     `json:"actionSuccessful"`
+	Messages         []string `json:"messages,omitempty"`
+}
+
+// failSave responds with the failure set with FailNextSave, if any. s.mu must be held.
+func (s *Server) failSave(w http.ResponseWriter) bool {
+
+	if !s.failNextSaveIsSet {
+		return false
+	}
+	writeJSON(w, response{Messages: s.failNextSave})
+	s.failNextSave = nil
+	s.failNextSaveIsSet = false
+
+	return true
+}
+
+// validate returns the reasons the row can't be saved next to the stored rows and the other rows of the same
+// request. s.mu must be held.
+func (s *Server) validate(row hrflow.WorkLogRow, batch ...hrflow.WorkLogRow) []string {
+
+	start, err := row.Start()
+	if err != nil {
+		return []string{"invalid start time"}
+	}
+	end, err := row.End()
+	if err != nil {
+		return []string{"invalid end time"}
+	}
+	if !end.After(start) {
+		return []string{"end time must be after start time"}
+	}
+
+	for _, other := range s.rows {
+		if other.Id != row.Id && overlaps(start, end, other) {
+			return []string{fmt.Sprintf("row overlaps with row %d", other.Id)}
+		}
+	}
+	for _, other := range batch {
+		if overlaps(start, end, other) {
+			return []string{fmt.Sprintf("row overlaps with another row of the request on %s", start.Format(dateFormat))}
+		}
+	}
+
+	return nil
+}
+
+// overlaps tells if other overlaps the time between start and end. Rows with invalid times never overlap.
+func overlaps(start, end time.Time, other hrflow.WorkLogRow) bool {
+
+	otherStart, err := other.Start()
+	if err != nil {
+		return false
+	}
+	otherEnd, err := other.End()
+	if err != nil {
+		return false
+	}
+
+	return start.Before(otherEnd) && otherStart.Before(end)
+}
+
+// addRow stores row with a new ID. s.mu must be held.
+func (s *Server) addRow(row hrflow.WorkLogRow) hrflow.WorkLogRow {
+
+	row.Id = s.nextID
+	s.nextID++
+	if row.Status == "" {
+		row.Status = hrflow.StatusNew
+	}
+	s.rows[row.Id] = row
+
+	return row
+}
+
+// copyRow moves a copy of row to date keeping its times.
+func copyRow(row hrflow.WorkLogRow, date time.Time) hrflow.WorkLogRow {
+
+	start, _ := row.Start()
+	end, _ := row.End()
+	day, _ := row.Day()
+	// Whole days keep the times the same over daylight saving time changes.
+	days := int(math.Round(date.Sub(day).Hours() / 24))
+
+	links := make([]hrflow.WorkLogRowLink, len(row.WorkLogRowLinks))
+	copy(links, row.WorkLogRowLinks)
+	factors := make([]hrflow.WorkLogFactor, len(row.WorkLogFactors))
+	copy(factors, row.WorkLogFactors)
+	row.WorkLogRowLinks = links
+	row.WorkLogFactors = factors
+	row.SetTimes(start.AddDate(0, 0, days), end.AddDate(0, 0, days))
+
+	return row
+}
+
+func (s *Server) newCode() string {
+	code := randomString()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.codes[code] = true
+	return code
+}
+
+func (s *Server) useCode(code string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ok := s.codes[code]
+	delete(s.codes, code)
+	return ok
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	json.NewEncoder(w).Encode(v)
+}
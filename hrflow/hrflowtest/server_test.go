@@ -0,0 +1,311 @@
+package hrflowtest_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/myyra/hrflow/hrflow/hrflowtest"
+)
+
+var monday = time.Date(2020, time.March, 2, 0, 0, 0, 0, time.Local)
+
+// at returns the time on the day offset days from monday.
+func at(days, hour, minute int) time.Time {
+	return monday.AddDate(0, 0, days).Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+// newClient starts a server and returns an authenticated client for it.
+func newClient(t *testing.T) (*hrflowtest.Server, *hrflow.Client) {
+
+	t.Helper()
+
+	server := hrflowtest.NewServer("user", "pass")
+	client := server.Client()
+	err := client.Authenticate()
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+
+	return server, client
+}
+
+// report creates a row from 8:00 to 16:00 on monday and the days after it given with copyDays.
+func report(t *testing.T, client *hrflow.Client, copyDays ...int) {
+
+	t.Helper()
+
+	var copyToDates []time.Time
+	for _, days := range copyDays {
+		copyToDates = append(copyToDates, monday.AddDate(0, 0, days))
+	}
+	err := client.NewWorkLog(at(0, 8, 0), at(0, 16, 0), "99002", "work", nil, true, copyToDates...)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	session := client.Session()
+	if len(session.XSRFToken) == 0 {
+		t.Error("no XSRF token after authenticating")
+	}
+	if len(session.Employments) != 1 {
+		t.Errorf("got %d employments, want 1", len(session.Employments))
+	}
+}
+
+func TestRestoreSession(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	session := client.Session()
+	if len(session.Cookies) == 0 {
+		t.Fatal("no cookies in the session")
+	}
+
+	restored := server.Client()
+	err := restored.RestoreSession(session)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = restored.WorkLogs(monday, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.Session().XSRFToken != session.XSRFToken {
+		t.Error("restored session was not accepted")
+	}
+}
+
+func TestAuthenticateWrongPassword(t *testing.T) {
+
+	server := hrflowtest.NewServer("user", "pass")
+	defer server.Close()
+
+	client := hrflow.NewClient("user", "wrong", hrflow.WithBaseURL(server.URL))
+	err := client.Authenticate()
+	if !errors.Is(err, hrflow.ErrInvalidCredentials) {
+		t.Errorf("got %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestExpiredSessionIsRenewed(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	report(t, client)
+	before := client.Session().XSRFToken
+	server.ExpireSessions()
+
+	rows, err := client.WorkLogs(monday, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 {
+		t.Errorf("got %d rows, want 1", len(rows))
+	}
+	if client.Session().XSRFToken == before {
+		t.Error("session was not renewed")
+	}
+}
+
+func TestFailNextSave(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	server.FailNextSave("period is closed")
+	err := client.NewWorkLog(at(0, 8, 0), at(0, 16, 0), "99002", "", nil, true)
+
+	var validationErr *hrflow.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+	if len(validationErr.Messages) != 1 || validationErr.Messages[0] != "period is closed" {
+		t.Errorf("got messages %v", validationErr.Messages)
+	}
+	if len(server.Rows()) != 0 {
+		t.Error("failed save stored rows")
+	}
+
+	// Only the next save fails.
+	report(t, client)
+}
+
+func TestListUpdateAndDelete(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	report(t, client, 1, 2)
+
+	rows, err := client.WorkLogs(monday, monday.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3", len(rows))
+	}
+	for i, row := range rows {
+		day, err := row.Day()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := monday.AddDate(0, 0, i); !day.Equal(want) {
+			t.Errorf("row %d is on %s, want %s", i, day, want)
+		}
+	}
+
+	row := rows[1]
+	row.SetTimes(at(1, 9, 0), at(1, 17, 30))
+	err = client.UpdateWorkLog(row)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.DeleteWorkLog(rows[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err = client.WorkLogs(monday, monday.AddDate(0, 0, 6))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows after deleting, want 2", len(rows))
+	}
+	if start, _ := rows[1].Start(); !start.Equal(at(1, 9, 0)) {
+		t.Errorf("updated row starts at %s", start)
+	}
+	if hours := rows[1].Hours(); hours != 8.5 {
+		t.Errorf("updated row has %v hours, want 8.5", hours)
+	}
+}
+
+func TestApprovedRowsAreRefused(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	report(t, client)
+	rows, err := client.WorkLogs(monday, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale := rows[0]
+	server.SetRowStatus(stale.Id, hrflow.StatusApproved)
+
+	// The client refuses rows it knows are approved.
+	rows, err = client.WorkLogs(monday, monday)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.UpdateWorkLog(rows[0]); err == nil {
+		t.Error("updating an approved row succeeded")
+	}
+	if err := client.DeleteWorkLog(rows[0]); err == nil {
+		t.Error("deleting an approved row succeeded")
+	}
+
+	// The server refuses rows approved after they were listed.
+	var validationErr *hrflow.ValidationError
+	if err := client.UpdateWorkLog(stale); !errors.As(err, &validationErr) {
+		t.Errorf("updating a stale approved row: got %v, want a ValidationError", err)
+	}
+	if err := client.DeleteWorkLog(stale); !errors.As(err, &validationErr) {
+		t.Errorf("deleting a stale approved row: got %v, want a ValidationError", err)
+	}
+	if len(server.Rows()) != 1 {
+		t.Error("approved row was deleted")
+	}
+}
+
+func TestOverlappingCopiesAreRefused(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	err := client.NewWorkLog(at(0, 8, 0), at(0, 16, 0), "99002", "", nil, true, monday.AddDate(0, 0, 1), monday.AddDate(0, 0, 1))
+
+	var validationErr *hrflow.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+	if len(server.Rows()) != 0 {
+		t.Errorf("refused request stored %d rows", len(server.Rows()))
+	}
+}
+
+func TestConcurrentRenewal(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	server.ExpireSessions()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := client.WorkLogs(monday, monday)
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			if len(client.Employments()) != 1 {
+				errs <- errors.New("employments missing during renewal")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestDimensions(t *testing.T) {
+
+	server, client := newClient(t)
+	defer server.Close()
+
+	project := "100 Internal"
+	err := client.NewWorkLog(at(0, 8, 0), at(0, 12, 0), "99002", "", &project, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dimensions := hrflow.Dimensions{
+		Department: &hrflow.ListItem{Value: "1", Label: "1 Development"},
+		Project:    &hrflow.ListItem{Value: "200", Label: "200 Customer"},
+	}
+	err = client.NewWorkLogWithDimensions(at(0, 12, 0), at(0, 16, 0), "99002", "", dimensions, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := server.Rows()
+	if len(rows) != 2 {
+		t.Fatalf("got %d rows, want 2", len(rows))
+	}
+	if rows[0].Project() != project || rows[0].Department() != "" {
+		t.Errorf("got project %q and department %q", rows[0].Project(), rows[0].Department())
+	}
+	if rows[1].Project() != "200 Customer" || rows[1].Department() != "1 Development" {
+		t.Errorf("got project %q and department %q", rows[1].Project(), rows[1].Department())
+	}
+}
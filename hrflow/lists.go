@@ -0,0 +1,140 @@
+package hrflow
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// IDs of the dimension lists rows can be linked to.
+const (
+	ListDepartments = "OSASTOT"
+	ListCostCenters = "KUSTPAIKAT"
+	ListProjects    = "PROJEKTIT"
+)
+
+// ListItem is a selectable value of a dimension list, such as a single project.
+type ListItem struct {
+	// Value is the ID of the item, usually a number.
+	Value string `json:"value"`
+	// Label is the human readable name of the item, usually starting with the value.
+	Label string `json:"label"`
+}
+
+// Dimensions are the list items a row is linked to. Nil items are left empty.
+type Dimensions struct {
+	Department *ListItem
+	CostCenter *ListItem
+	Project    *ListItem
+}
+
+// projectItem returns the item of a project label starting with its value, like "123 Project", or nil if project
+// is nil.
+func projectItem(project *string) *ListItem {
+
+	if project == nil {
+		return nil
+	}
+
+	return &ListItem{
+		Value: strings.Split(*project, " ")[0],
+		Label: *project,
+	}
+}
+
+// ListItems returns the items of the dimension list listID along with their labels.
+func (c *Client) ListItems(listID string) ([]ListItem, error) {
+	return c.ListItemsContext(context.Background(), listID)
+}
+
+// ListItemsContext is like ListItems but the request is bound to ctx.
+func (c *Client) ListItemsContext(ctx context.Context, listID string) ([]ListItem, error) {
+
+	workLogRequest := c.NewWorkLogRequest()
+	workLogRequest.GetListsLabels = true
+	workLogRequestJSON, err := json.Marshal(workLogRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling work log request")
+	}
+	body := url.Values{}
+	body.Add("listId", listID)
+	body.Add("workLogRequest", string(workLogRequestJSON))
+
+	var items []ListItem
+
+	err = c.postForm(ctx, c.endpoint(ListValuesPath), body, &items)
+	if err != nil {
+		return nil, errors.Wrapf(err, "list %s request", listID)
+	}
+
+	return items, nil
+}
+
+// Departments returns the departments rows can be linked to.
+func (c *Client) Departments() ([]ListItem, error) {
+	return c.ListItems(ListDepartments)
+}
+
+// DepartmentsContext is like Departments but the request is bound to ctx.
+func (c *Client) DepartmentsContext(ctx context.Context) ([]ListItem, error) {
+	return c.ListItemsContext(ctx, ListDepartments)
+}
+
+// CostCenters returns the cost centers rows can be linked to.
+func (c *Client) CostCenters() ([]ListItem, error) {
+	return c.ListItems(ListCostCenters)
+}
+
+// CostCentersContext is like CostCenters but the request is bound to ctx.
+func (c *Client) CostCentersContext(ctx context.Context) ([]ListItem, error) {
+	return c.ListItemsContext(ctx, ListCostCenters)
+}
+
+// Projects returns the projects rows can be linked to.
+func (c *Client) Projects() ([]ListItem, error) {
+	return c.ListItems(ListProjects)
+}
+
+// ProjectsContext is like Projects but the request is bound to ctx.
+func (c *Client) ProjectsContext(ctx context.Context) ([]ListItem, error) {
+	return c.ListItemsContext(ctx, ListProjects)
+}
+
+// SearchListItems returns the items whose value or label contains query, ignoring case.
+func SearchListItems(items []ListItem, query string) []ListItem {
+
+	query = strings.ToLower(query)
+
+	matches := []ListItem{}
+	for _, item := range items {
+		if strings.Contains(strings.ToLower(item.Value), query) || strings.Contains(strings.ToLower(item.Label), query) {
+			matches = append(matches, item)
+		}
+	}
+
+	return matches
+}
+
+// FindListItem returns the item whose value or label equals s, ignoring case.
+// If s starts with a value followed by a space, like labels usually do, the value is enough to match.
+func FindListItem(items []ListItem, s string) (ListItem, bool) {
+
+	s = strings.TrimSpace(s)
+	value := strings.Split(s, " ")[0]
+
+	for _, item := range items {
+		if strings.EqualFold(item.Label, s) || item.Value == s {
+			return item, true
+		}
+	}
+	for _, item := range items {
+		if item.Value == value {
+			return item, true
+		}
+	}
+
+	return ListItem{}, false
+}
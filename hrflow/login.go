@@ -0,0 +1,177 @@
+package hrflow
+
+import (
+	"encoding/json"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/pkg/errors"
+)
+
+// page is an HTML page of the login process.
+type page struct {
+	// url is the final URL of the page after redirects, relative links are resolved against it.
+	url     *url.URL
+	doc     *goquery.Document
+	content string
+}
+
+// form is a parsed HTML form ready to be submitted.
+type form struct {
+	action string
+	values url.Values
+}
+
+// loginFormURL returns the URL the credentials are posted to from the login page.
+func loginFormURL(p *page) (string, error) {
+
+	action, exists := p.doc.Find("#options").Attr("action")
+	if !exists {
+		return "", errors.Wrap(ErrUnexpectedPage, "login form not found")
+	}
+
+	return p.resolve(action)
+}
+
+// checkLoginRejected returns ErrInvalidCredentials if the page is the login page shown again after a failed login.
+func checkLoginRejected(p *page) error {
+
+	if message := strings.TrimSpace(p.doc.Find("#errorText").Text()); len(message) != 0 {
+		return errors.Wrap(ErrInvalidCredentials, message)
+	}
+	if p.doc.Find("input[name=Password]").Length() != 0 {
+		return ErrInvalidCredentials
+	}
+
+	return nil
+}
+
+// firstForm parses the first form of the page, which in the auth and OIDC steps is submitted automatically by
+// JavaScript in the browser.
+func firstForm(p *page) (form, error) {
+
+	selection := p.doc.Find("form").First()
+	if selection.Length() == 0 {
+		return form{}, errors.Wrap(ErrUnexpectedPage, "form not found")
+	}
+
+	action, exists := selection.Attr("action")
+	if !exists {
+		return form{}, errors.Wrap(ErrUnexpectedPage, "form action attribute not found")
+	}
+	actionURL, err := p.resolve(action)
+	if err != nil {
+		return form{}, err
+	}
+
+	return form{
+		action: actionURL,
+		values: formValues(selection),
+	}, nil
+}
+
+// formValues returns the values a browser would submit from the form: named inputs, except unchecked checkboxes
+// and radio buttons, selected options of selects, and textareas.
+func formValues(selection *goquery.Selection) url.Values {
+
+	values := url.Values{}
+
+	selection.Find("input").Each(func(i int, s *goquery.Selection) {
+		name, exists := s.Attr("name")
+		if !exists || len(name) == 0 {
+			return
+		}
+		switch strings.ToLower(s.AttrOr("type", "text")) {
+		case "submit", "button", "image", "reset", "file":
+			return
+		case "checkbox", "radio":
+			if _, checked := s.Attr("checked"); !checked {
+				return
+			}
+			values.Add(name, s.AttrOr("value", "on"))
+			return
+		}
+		values.Add(name, s.AttrOr("value", ""))
+	})
+
+	selection.Find("select").Each(func(i int, s *goquery.Selection) {
+		name, exists := s.Attr("name")
+		if !exists || len(name) == 0 {
+			return
+		}
+		options := s.Find("option[selected]")
+		if options.Length() == 0 {
+			options = s.Find("option").First()
+		}
+		options.Each(func(i int, option *goquery.Selection) {
+			value, exists := option.Attr("value")
+			if !exists {
+				value = option.Text()
+			}
+			values.Add(name, value)
+		})
+	})
+
+	selection.Find("textarea").Each(func(i int, s *goquery.Selection) {
+		name, exists := s.Attr("name")
+		if !exists || len(name) == 0 {
+			return
+		}
+		values.Add(name, s.Text())
+	})
+
+	return values
+}
+
+// resolve resolves a possibly relative URL found on the page.
+func (p *page) resolve(ref string) (string, error) {
+
+	u, err := url.Parse(ref)
+	if err != nil {
+		return "", errors.Wrapf(ErrUnexpectedPage, "invalid url %s", ref)
+	}
+	if p.url == nil {
+		return u.String(), nil
+	}
+
+	return p.url.ResolveReference(u).String(), nil
+}
+
+var (
+	xsrfTokenRegex   = regexp.MustCompile(`var RequestVerificationToken = '([^']*)'`)
+	userRoleKeyRegex = regexp.MustCompile(`var SELECTED_USER_AND_ROLEKEY = "([^"]*)";`)
+	employmentsRegex = regexp.MustCompile(`var employments = `)
+)
+
+// parseLoggedInPage reads the session values from the script variables of the page shown after logging in.
+func parseLoggedInPage(p *page) (Session, error) {
+
+	xsrfToken := xsrfTokenRegex.FindStringSubmatch(p.content)
+	if xsrfToken == nil {
+		return Session{}, errors.Wrap(ErrUnexpectedPage, "request verification token not found")
+	}
+
+	userRoleKey := userRoleKeyRegex.FindStringSubmatch(p.content)
+	if userRoleKey == nil {
+		return Session{}, errors.Wrap(ErrUnexpectedPage, "user and role key not found")
+	}
+
+	loc := employmentsRegex.FindStringIndex(p.content)
+	if loc == nil {
+		return Session{}, errors.Wrap(ErrUnexpectedPage, "employments not found")
+	}
+	// The decoder stops after the array, so whatever follows it in the script doesn't matter.
+	var employments []Employment
+	err := json.NewDecoder(strings.NewReader(p.content[loc[1]:])).Decode(&employments)
+	if err != nil {
+		return Session{}, errors.Wrapf(ErrUnexpectedPage, "parsing employments: %v", err)
+	}
+
+	return Session{
+		XSRFToken:   xsrfToken[1],
+		UserRoleKey: userRoleKey[1],
+		Employments: employments,
+	}, nil
+}
@@ -0,0 +1,202 @@
+package hrflow
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// fixturePage loads an HTML fixture from testdata as if it was served from pageURL.
+func fixturePage(t *testing.T, name, pageURL string) *page {
+
+	t.Helper()
+
+	content, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return contentPage(t, string(content), pageURL)
+}
+
+func contentPage(t *testing.T, content, pageURL string) *page {
+
+	t.Helper()
+
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &page{url: u, doc: doc, content: content}
+}
+
+func TestLoginFormURL(t *testing.T) {
+
+	p := fixturePage(t, "login.html", "https://hrflow.example.com/adfs/ls/?client-request-id=1234")
+	got, err := loginFormURL(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "https://hrflow.example.com/adfs/ls/?client-request-id=1234&RedirectToIdentityProvider=AD+AUTHORITY"
+	if got != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestLoginFormURLWithoutOptions(t *testing.T) {
+
+	p := fixturePage(t, "login_no_options.html", "https://hrflow.example.com/KirjaamoWeb/login/Employee")
+	_, err := loginFormURL(p)
+	if !errors.Is(err, ErrUnexpectedPage) {
+		t.Errorf("got %v, want ErrUnexpectedPage", err)
+	}
+}
+
+func TestCheckLoginRejected(t *testing.T) {
+
+	tests := []struct {
+		fixture string
+		want    error
+	}{
+		{"login_error.html", ErrInvalidCredentials},
+		{"login_repeated.html", ErrInvalidCredentials},
+		{"auth_form.html", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.fixture, func(t *testing.T) {
+			p := fixturePage(t, test.fixture, "https://hrflow.example.com/adfs/ls/")
+			err := checkLoginRejected(p)
+			if !errors.Is(err, test.want) {
+				t.Errorf("got %v, want %v", err, test.want)
+			}
+		})
+	}
+}
+
+func TestCheckLoginRejectedMessage(t *testing.T) {
+
+	p := fixturePage(t, "login_error.html", "https://hrflow.example.com/adfs/ls/")
+	err := checkLoginRejected(p)
+	if err == nil || !strings.Contains(err.Error(), "Incorrect user ID or password") {
+		t.Errorf("got %v, want the message of the page", err)
+	}
+}
+
+func TestFirstForm(t *testing.T) {
+
+	p := fixturePage(t, "auth_form.html", "https://hrflow.example.com/adfs/ls/")
+	got, err := firstForm(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.action != "https://hrflow.example.com/adfs/auth" {
+		t.Errorf("got action %s", got.action)
+	}
+	want := url.Values{
+		"code":     {"abc123"},
+		"state":    {"state&value"},
+		"empty":    {""},
+		"remember": {"on"},
+		"choice":   {"b"},
+		"selected": {"2"},
+		"default":  {"First"},
+		"notes":    {"some text"},
+	}
+	if !reflect.DeepEqual(got.values, want) {
+		t.Errorf("got values %v, want %v", got.values, want)
+	}
+}
+
+func TestFirstFormRelativeAction(t *testing.T) {
+
+	p := fixturePage(t, "oidc_form.html", "https://hrflow.example.com/KirjaamoWeb/login/Employee")
+	got, err := firstForm(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "https://hrflow.example.com/KirjaamoWeb/login/signin-oidc"; got.action != want {
+		t.Errorf("got action %s, want %s", got.action, want)
+	}
+	if got.values.Get("id_token") != "token" {
+		t.Errorf("got values %v", got.values)
+	}
+}
+
+func TestFirstFormWithoutForm(t *testing.T) {
+
+	p := contentPage(t, "<html><body><p>Working...</p></body></html>", "https://hrflow.example.com/")
+	_, err := firstForm(p)
+	if !errors.Is(err, ErrUnexpectedPage) {
+		t.Errorf("got %v, want ErrUnexpectedPage", err)
+	}
+}
+
+func TestParseLoggedInPage(t *testing.T) {
+
+	p := fixturePage(t, "logged_in.html", "https://hrflow.example.com/KirjaamoWeb/")
+	got, err := parseLoggedInPage(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.XSRFToken != "xsrf-token-value" {
+		t.Errorf("got token %s", got.XSRFToken)
+	}
+	if got.UserRoleKey != "u_EMPLOYEE" {
+		t.Errorf("got user role key %s", got.UserRoleKey)
+	}
+	if len(got.Employments) != 2 || got.Employments[0].EmploymentID != 1001 || !got.Employments[1].IsPassive {
+		t.Errorf("got employments %+v", got.Employments)
+	}
+}
+
+func TestParseLoggedInPageMissingValues(t *testing.T) {
+
+	content, err := ioutil.ReadFile(filepath.Join("testdata", "logged_in.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		old, new    string
+		wantMessage string
+	}{
+		{"token", "var RequestVerificationToken = 'xsrf-token-value';", "", "request verification token"},
+		{"role key", `var SELECTED_USER_AND_ROLEKEY = "u_EMPLOYEE";`, "", "user and role key"},
+		{"employments", "var employments = ", "var other = ", "employments not found"},
+		{"broken employments", "var employments = [{", "var employments = [{,", "parsing employments"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			modified := strings.Replace(string(content), test.old, test.new, 1)
+			if modified == string(content) {
+				t.Fatalf("fixture doesn't contain %q", test.old)
+			}
+			p := contentPage(t, modified, "https://hrflow.example.com/KirjaamoWeb/")
+			_, err := parseLoggedInPage(p)
+			if !errors.Is(err, ErrUnexpectedPage) {
+				t.Errorf("got %v, want ErrUnexpectedPage", err)
+			}
+			if err != nil && !strings.Contains(err.Error(), test.wantMessage) {
+				t.Errorf("got %v, want it to mention %s", err, test.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package hrflow
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MissingWorkdays returns the midnights of the workdays of workCal between startDate and endDate, inclusive,
+// that don't have any rows. Rows of any salary group count as reported, including absences, but rejected rows
+// don't, as they have to be reported again.
+func MissingWorkdays(rows []WorkLogRow, workCal *WorkCalendar, startDate, endDate time.Time) ([]time.Time, error) {
+
+	reported := map[string]bool{}
+	for _, row := range rows {
+		if row.Status == StatusRejected {
+			continue
+		}
+		day, err := row.Day()
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing work log date")
+		}
+		reported[dateKey(day)] = true
+	}
+
+	var missing []time.Time
+	for _, date := range workCal.Workdays(startDate, endDate) {
+		if !reported[dateKey(date)] {
+			missing = append(missing, date)
+		}
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,40 @@
+package hrflow
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMissingWorkdays(t *testing.T) {
+
+	calendar := newWorkCalendar(t)
+	// Absences are reported with other salary groups than work.
+	absence := newRow(newDate(2026, time.April, 7), 8, 16, false, StatusApproved)
+	absence.SalaryGroupValue = "20010"
+	rows := []WorkLogRow{
+		newRow(newDate(2026, time.March, 30), 8, 16, true, StatusApproved),
+		// Rejected rows have to be reported again.
+		newRow(newDate(2026, time.March, 31), 8, 16, true, StatusRejected),
+		// A rejected row doesn't hide the other rows of the day.
+		newRow(newDate(2026, time.April, 1), 8, 12, true, StatusRejected),
+		newRow(newDate(2026, time.April, 1), 12, 16, false, StatusSent),
+		// Rows on days off don't matter.
+		newRow(newDate(2026, time.April, 3), 10, 12, false, StatusNew),
+		absence,
+	}
+
+	missing, err := MissingWorkdays(rows, calendar, newDate(2026, time.March, 30), newDate(2026, time.April, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []time.Time{newDate(2026, time.March, 31), newDate(2026, time.April, 2), newDate(2026, time.April, 8)}
+	if len(missing) != len(want) {
+		t.Fatalf("got %v, want %v", missing, want)
+	}
+	for i := range missing {
+		if !missing[i].Equal(want[i]) {
+			t.Errorf("got %s, want %s", missing[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package hrflow
+
+import (
+	"log"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+)
+
+// Option configures a Client created with NewClient.
+type Option func(*Client)
+
+// WithBaseURL makes the client use the service at baseURL, such as a test environment or a proxy, instead of DefaultBaseURL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithLoginPath makes the client start the login process from loginPath instead of DefaultLoginPath.
+func WithLoginPath(loginPath string) Option {
+	return func(c *Client) {
+		c.loginPath = loginPath
+	}
+}
+
+// WithHTTPClient makes the client do requests with a copy of httpClient. The login process needs cookies,
+// so a cookie jar is added to the copy if httpClient doesn't have one.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		client := *httpClient
+		if client.Jar == nil {
+			client.Jar, _ = cookiejar.New(nil)
+		}
+		c.HttpClient = &client
+	}
+}
+
+// WithTimeout limits the time of each request, including redirects and reading the response. By default there is no limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// WithClock makes the client use now instead of time.Now for timestamps and default dates, e.g. to make tests deterministic.
+func WithClock(now func() time.Time) Option {
+	return func(c *Client) {
+		c.clock = now
+	}
+}
+
+// WithLogger makes the client log its requests and re-authentications to logger. By default nothing is logged.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
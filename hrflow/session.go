@@ -0,0 +1,94 @@
+package hrflow
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Session is the state of an authenticated client. It can be stored and restored with RestoreSession to avoid
+// running the whole authentication process again. Sessions contain the login cookies, so store them privately.
+type Session struct {
+	BaseURL     string         `json:"baseUrl"`
+	Username    string         `json:"username"`
+	Cookies     []*http.Cookie `json:"cookies"`
+	XSRFToken   string         `json:"xsrfToken"`
+	UserRoleKey string         `json:"userRoleKey"`
+	Employments []Employment   `json:"employments"`
+}
+
+// Session returns the current session of the client.
+func (c *Client) Session() Session {
+
+	var cookies []*http.Cookie
+	if u, err := c.sessionURL(); err == nil && c.HttpClient.Jar != nil {
+		cookies = c.HttpClient.Jar.Cookies(u)
+	}
+
+	c.sessionMu.RLock()
+	defer c.sessionMu.RUnlock()
+
+	return Session{
+		BaseURL:     c.baseURL,
+		Username:    c.username,
+		Cookies:     cookies,
+		XSRFToken:   c.xsrfToken,
+		UserRoleKey: c.userRoleKey,
+		Employments: c.employments,
+	}
+}
+
+// RestoreSession replaces the session of the client with s. The session must belong to the same user and service.
+// If the backend no longer accepts the session, the client authenticates again on the next request.
+func (c *Client) RestoreSession(s Session) error {
+
+	if s.Username != c.username {
+		return errors.New("session belongs to another user")
+	}
+	if s.BaseURL != c.baseURL {
+		return errors.New("session belongs to another service")
+	}
+	if c.HttpClient.Jar == nil {
+		return errors.New("http client has no cookie jar")
+	}
+	u, err := c.sessionURL()
+	if err != nil {
+		return err
+	}
+
+	c.HttpClient.Jar.SetCookies(u, s.Cookies)
+	c.setSession(s.XSRFToken, s.UserRoleKey, s.Employments)
+
+	return nil
+}
+
+// CheckSession does a lightweight request to make sure the client has a session the backend accepts,
+// authenticating again if needed.
+func (c *Client) CheckSession() error {
+	return c.CheckSessionContext(context.Background())
+}
+
+// CheckSessionContext is like CheckSession but the requests are bound to ctx.
+func (c *Client) CheckSessionContext(ctx context.Context) error {
+
+	today := c.now()
+	_, err := c.CalendarContext(ctx, today, today)
+
+	return err
+}
+
+// sessionPath is the path of the application the endpoints are under. Its cookies can be scoped to it.
+const sessionPath = "/KirjaamoWeb/"
+
+// sessionURL is the URL whose cookies are stored in a Session. The cookies of the whole site are sent to it too.
+func (c *Client) sessionURL() (*url.URL, error) {
+
+	u, err := url.Parse(c.endpoint(sessionPath))
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing base url")
+	}
+
+	return u, nil
+}
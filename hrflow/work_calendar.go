@@ -0,0 +1,97 @@
+package hrflow
+
+import (
+	"time"
+)
+
+// WorkCalendar answers questions about workdays from calendar days, e.g. the ones returned by Client.Calendar.
+// Days are matched by their date, ignoring the time and location. Days missing from the calendar are
+// workdays from Monday to Friday.
+type WorkCalendar struct {
+	days map[string]CalendarDay
+}
+
+// NewWorkCalendar creates a work calendar from days.
+func NewWorkCalendar(days []CalendarDay) *WorkCalendar {
+
+	w := &WorkCalendar{days: map[string]CalendarDay{}}
+	for _, day := range days {
+		w.days[dateKey(day.Date)] = day
+	}
+
+	return w
+}
+
+// dateKey identifies the date of t regardless of its time and location.
+func dateKey(t time.Time) string {
+	return t.Format("2006-01-02")
+}
+
+// Day returns the calendar day of date, and if the calendar has it at all.
+func (w *WorkCalendar) Day(date time.Time) (CalendarDay, bool) {
+
+	day, ok := w.days[dateKey(date)]
+
+	return day, ok
+}
+
+// IsWorkday tells if date is a workday.
+func (w *WorkCalendar) IsWorkday(date time.Time) bool {
+
+	day, ok := w.Day(date)
+	if ok {
+		return day.Workday
+	}
+
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+}
+
+// NextWorkday returns the first workday after date.
+func (w *WorkCalendar) NextWorkday(date time.Time) time.Time {
+	return w.AddWorkdays(date, 1)
+}
+
+// AddWorkdays returns the date n workdays after date, or before it if n is negative.
+// The time of day of date is kept.
+func (w *WorkCalendar) AddWorkdays(date time.Time, n int) time.Time {
+
+	step := 1
+	if n < 0 {
+		step, n = -1, -n
+	}
+	for n > 0 {
+		date = date.AddDate(0, 0, step)
+		if w.IsWorkday(date) {
+			n--
+		}
+	}
+
+	return date
+}
+
+// Workdays returns the midnights of the workdays between start and end, inclusive, in the location of start.
+func (w *WorkCalendar) Workdays(start, end time.Time) []time.Time {
+
+	start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, start.Location())
+
+	var workdays []time.Time
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		if w.IsWorkday(date) {
+			workdays = append(workdays, date)
+		}
+	}
+
+	return workdays
+}
+
+// WorkdaysBetween returns the number of workdays between start and end, inclusive.
+func (w *WorkCalendar) WorkdaysBetween(start, end time.Time) int {
+	return len(w.Workdays(start, end))
+}
+
+// ExpectedHours returns the hours that should be worked between start and end, inclusive, when a workday is
+// dailyHours long.
+func (w *WorkCalendar) ExpectedHours(start, end time.Time, dailyHours float64) float64 {
+	return float64(w.WorkdaysBetween(start, end)) * dailyHours
+}
@@ -0,0 +1,132 @@
+package hrflow
+
+import (
+	"testing"
+	"time"
+)
+
+// newWorkCalendar returns the calendar of 2026 from Monday 30.3. to Friday 10.4., with Easter from 3.4. to 6.4.
+func newWorkCalendar(t *testing.T) *WorkCalendar {
+
+	t.Helper()
+
+	days, err := FinnishCalendar{}.Calendar(newDate(2026, time.March, 30), newDate(2026, time.April, 10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return NewWorkCalendar(days)
+}
+
+func TestIsWorkday(t *testing.T) {
+
+	calendar := newWorkCalendar(t)
+
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{newDate(2026, time.April, 2), true},
+		{newDate(2026, time.April, 3), false},
+		{newDate(2026, time.April, 6), false},
+		{time.Date(2026, time.April, 6, 15, 0, 0, 0, time.UTC), false},
+		{newDate(2026, time.April, 7), true},
+		// Days missing from the calendar are workdays from Monday to Friday.
+		{newDate(2026, time.May, 4), true},
+		{newDate(2026, time.May, 2), false},
+		{newDate(2026, time.May, 3), false},
+	}
+
+	for _, test := range tests {
+		if got := calendar.IsWorkday(test.date); got != test.want {
+			t.Errorf("got %v for %s, want %v", got, test.date, test.want)
+		}
+	}
+}
+
+func TestAddWorkdays(t *testing.T) {
+
+	calendar := newWorkCalendar(t)
+	thursday := time.Date(2026, time.April, 2, 8, 30, 0, 0, time.Local)
+
+	tests := []struct {
+		date time.Time
+		n    int
+		want time.Time
+	}{
+		{thursday, 0, thursday},
+		{thursday, 1, time.Date(2026, time.April, 7, 8, 30, 0, 0, time.Local)},
+		{thursday, 2, time.Date(2026, time.April, 8, 8, 30, 0, 0, time.Local)},
+		{thursday, -1, time.Date(2026, time.April, 1, 8, 30, 0, 0, time.Local)},
+		{newDate(2026, time.April, 7), -1, newDate(2026, time.April, 2)},
+		{newDate(2026, time.April, 7), -3, newDate(2026, time.March, 31)},
+		// A holiday is not a workday to count from.
+		{newDate(2026, time.April, 4), 1, newDate(2026, time.April, 7)},
+		{newDate(2026, time.April, 4), -1, newDate(2026, time.April, 2)},
+	}
+
+	for _, test := range tests {
+		if got := calendar.AddWorkdays(test.date, test.n); !got.Equal(test.want) {
+			t.Errorf("got %s for %s%+d, want %s", got, test.date, test.n, test.want)
+		}
+	}
+	if got := calendar.NextWorkday(thursday); !got.Equal(calendar.AddWorkdays(thursday, 1)) {
+		t.Errorf("got next workday %s", got)
+	}
+}
+
+func TestWorkdays(t *testing.T) {
+
+	calendar := newWorkCalendar(t)
+
+	got := calendar.Workdays(time.Date(2026, time.April, 1, 12, 0, 0, 0, time.Local), newDate(2026, time.April, 8))
+	want := []time.Time{newDate(2026, time.April, 1), newDate(2026, time.April, 2), newDate(2026, time.April, 7),
+		newDate(2026, time.April, 8)}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("got %s, want %s", got[i], want[i])
+		}
+	}
+
+	if got := calendar.Workdays(newDate(2026, time.April, 3), newDate(2026, time.April, 6)); len(got) != 0 {
+		t.Errorf("got workdays %v over Easter", got)
+	}
+	if got := calendar.Workdays(newDate(2026, time.April, 8), newDate(2026, time.April, 7)); len(got) != 0 {
+		t.Errorf("got workdays %v when end is before start", got)
+	}
+}
+
+func TestWorkdaysBetween(t *testing.T) {
+
+	calendar := newWorkCalendar(t)
+
+	tests := []struct {
+		start, end time.Time
+		want       int
+	}{
+		{newDate(2026, time.March, 30), newDate(2026, time.April, 10), 8},
+		{newDate(2026, time.April, 2), newDate(2026, time.April, 2), 1},
+		{newDate(2026, time.April, 3), newDate(2026, time.April, 6), 0},
+		// Days after the calendar are counted from the weekdays.
+		{newDate(2026, time.April, 10), newDate(2026, time.April, 17), 6},
+	}
+
+	for _, test := range tests {
+		if got := calendar.WorkdaysBetween(test.start, test.end); got != test.want {
+			t.Errorf("got %d workdays from %s to %s, want %d", got, test.start, test.end, test.want)
+		}
+	}
+}
+
+func TestExpectedHours(t *testing.T) {
+
+	calendar := newWorkCalendar(t)
+
+	got := calendar.ExpectedHours(newDate(2026, time.March, 30), newDate(2026, time.April, 10), 7.5)
+	if got != 60 {
+		t.Errorf("got %v expected hours, want 60", got)
+	}
+}
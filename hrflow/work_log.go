@@ -1,16 +1,27 @@
 package hrflow
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"net/url"
-	"strings"
+	"strconv"
 	"time"
 
 	"github.com/pkg/errors"
 )
 
+// Statuses a work log row can be in.
+const (
+	StatusNew      = "NEW"
+	StatusSent     = "SENT"
+	StatusApproved = "APPROVED"
+	StatusRejected = "REJECTED"
+)
+
+// allStatuses is used when listing rows regardless of their status.
+var allStatuses = []string{StatusNew, StatusSent, StatusApproved, StatusRejected}
+
 type WorkLogRow struct {
 	// id is always 0.
 	Id int64 `json:"id"`
@@ -60,6 +71,135 @@ type WorkLogRow struct {
 	EndDate           *string          `json:"endDate"`
 }
 
+// Day returns the date of the row at midnight.
+func (r WorkLogRow) Day() (time.Time, error) {
+	return time.ParseInLocation(hrFlowTimeFormat, r.Date, time.Local)
+}
+
+// Start returns the start time of the row.
+func (r WorkLogRow) Start() (time.Time, error) {
+	return time.ParseInLocation(hrFlowTimeFormat, r.StartTime, time.Local)
+}
+
+// End returns the end time of the row.
+func (r WorkLogRow) End() (time.Time, error) {
+	return time.ParseInLocation(hrFlowTimeFormat, r.EndTime, time.Local)
+}
+
+// Hours returns the reported amount of hours, or 0 if the amount can't be parsed.
+func (r WorkLogRow) Hours() float64 {
+	hours, err := strconv.ParseFloat(r.MainAmount, 64)
+	if err != nil {
+		return 0
+	}
+	return hours
+}
+
+// WorkedHours returns the reported hours with the lunch break deducted if the row deducts it.
+func (r WorkLogRow) WorkedHours() float64 {
+
+	hours := r.Hours()
+	if r.CutLunchFromAmount == "Y" {
+		hours -= float64(r.LunchBreak) / 60
+	}
+
+	return hours
+}
+
+// Department returns the label of the department linked to the row, or an empty string if there is none.
+func (r WorkLogRow) Department() string {
+	return r.linkLabel(ListDepartments)
+}
+
+// CostCenter returns the label of the cost center linked to the row, or an empty string if there is none.
+func (r WorkLogRow) CostCenter() string {
+	return r.linkLabel(ListCostCenters)
+}
+
+// Project returns the label of the project linked to the row, or an empty string if there is none.
+func (r WorkLogRow) Project() string {
+	return r.linkLabel(ListProjects)
+}
+
+func (r WorkLogRow) linkLabel(listID string) string {
+	for _, link := range r.WorkLogRowLinks {
+		if link.ListID == listID && link.Label != nil {
+			return *link.Label
+		}
+	}
+	return ""
+}
+
+// Comment returns the entry text of the row.
+func (r WorkLogRow) Comment() string {
+	if r.EntryText == nil {
+		return ""
+	}
+	return *r.EntryText
+}
+
+// Locked tells if the row has already been approved and can no longer be changed or deleted.
+func (r WorkLogRow) Locked() bool {
+	return r.Status == StatusApproved
+}
+
+// SetTimes moves the row to the given start and end times and updates the reported amount to match.
+func (r *WorkLogRow) SetTimes(startTime, endTime time.Time) {
+
+	hours := endTime.Sub(startTime).Hours()
+	date := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, startTime.Location())
+
+	r.Date = date.Format(hrFlowTimeFormat)
+	r.StartTime = startTime.Format(hrFlowTimeFormat)
+	r.EndTime = endTime.Format(hrFlowTimeFormat)
+	r.MainAmount = fmt.Sprintf("%.3f", hours)
+	for i := range r.WorkLogFactors {
+		r.WorkLogFactors[i].Amount = hours
+	}
+}
+
+// SetLunch sets whether a 30 minute lunch break is deducted from the row.
+func (r *WorkLogRow) SetLunch(lunch bool) {
+	if lunch {
+		r.LunchBreak = 30
+		r.CutLunchFromAmount = "Y"
+	} else {
+		r.LunchBreak = 0
+		r.CutLunchFromAmount = "N"
+	}
+}
+
+// SetDepartment replaces the department linked to the row, nil removes the department.
+func (r *WorkLogRow) SetDepartment(department *ListItem) {
+	r.setLink(7, ListDepartments, department)
+}
+
+// SetCostCenter replaces the cost center linked to the row, nil removes the cost center.
+func (r *WorkLogRow) SetCostCenter(costCenter *ListItem) {
+	r.setLink(8, ListCostCenters, costCenter)
+}
+
+// SetProject replaces the project linked to the row, nil removes the project.
+func (r *WorkLogRow) SetProject(project *ListItem) {
+	r.setLink(9, ListProjects, project)
+}
+
+// setLink replaces the value of the link to listID, adding the link if the row doesn't have one.
+func (r *WorkLogRow) setLink(colNumber int64, listID string, item *ListItem) {
+
+	link := newWorkLogRowLink(colNumber, listID, item)
+
+	for i := range r.WorkLogRowLinks {
+		if r.WorkLogRowLinks[i].ListID == listID {
+			link.Id = r.WorkLogRowLinks[i].Id
+			link.WorkLogRowID = r.WorkLogRowLinks[i].WorkLogRowID
+			r.WorkLogRowLinks[i] = link
+			return
+		}
+	}
+	r.WorkLogRowLinks = append(r.WorkLogRowLinks, link)
+}
+
 type WorkLogFactor struct {
 	// id seems to always be 0
 	Id int64 `json:"id"`
@@ -128,11 +268,22 @@ func (c *Client) NewWorkLogRow(
 	comment string,
 	project *string,
 ) WorkLogRow {
+	return c.NewWorkLogRowWithDimensions(employmentID, personID, groupID, startTime, endTime, salaryGroupValue, comment, Dimensions{Project: projectItem(project)})
+}
+
+// NewWorkLogRowWithDimensions is like NewWorkLogRow but links the row to all of the dimensions.
+func (c *Client) NewWorkLogRowWithDimensions(
+	employmentID, personID, groupID int64,
+	startTime, endTime time.Time,
+	salaryGroupValue string,
+	comment string,
+	dimensions Dimensions,
+) WorkLogRow {
 
 	hours := endTime.Sub(startTime).Hours()
 	date := time.Date(startTime.Year(), startTime.Month(), startTime.Day(), 0, 0, 0, 0, startTime.Location())
 
-	now := time.Now().Format(hrFlowTimeFormat)
+	now := c.now().Format(hrFlowTimeFormat)
 	return WorkLogRow{
 		Id:                 0,
 		CustomerID:         1,
@@ -156,16 +307,16 @@ func (c *Client) NewWorkLogRow(
 		EntryText:          &comment,
 		EntryTextType:      "TEXTHASHTAG",
 		WorkLogRowLinks: []WorkLogRowLink{
-			c.NewWorkLogRowLink(7, "OSASTOT", nil),
-			c.NewWorkLogRowLink(8, "KUSTPAIKAT", nil),
-			c.NewWorkLogRowLink(9, "PROJEKTIT", project),
+			newWorkLogRowLink(7, ListDepartments, dimensions.Department),
+			newWorkLogRowLink(8, ListCostCenters, dimensions.CostCenter),
+			newWorkLogRowLink(9, ListProjects, dimensions.Project),
 		},
 		WorkLogComments: []string{},
 	}
 }
 
 func (c *Client) NewWorkLogFactor(hours float64) WorkLogFactor {
-	now := time.Now().Format(hrFlowTimeFormat)
+	now := c.now().Format(hrFlowTimeFormat)
 	return WorkLogFactor{
 		Id:           0,
 		WorkLogRowID: 0,
@@ -179,7 +330,12 @@ func (c *Client) NewWorkLogFactor(hours float64) WorkLogFactor {
 	}
 }
 
+// NewWorkLogRowLink links a row to project, a label starting with the value of the item, e.g. "123 Project".
 func (c *Client) NewWorkLogRowLink(colNumber int64, listID string, project *string) WorkLogRowLink {
+	return newWorkLogRowLink(colNumber, listID, projectItem(project))
+}
+
+func newWorkLogRowLink(colNumber int64, listID string, item *ListItem) WorkLogRowLink {
 	link := WorkLogRowLink{
 		Id:                  0,
 		WorkLogRowID:        0,
@@ -189,9 +345,10 @@ func (c *Client) NewWorkLogRowLink(colNumber int64, listID string, project *stri
 		DimensionSourceType: "PARAM",
 		ListID:              listID,
 	}
-	if project != nil {
-		link.Value = &strings.Split(*project, " ")[0]
-		link.Label = project
+	if item != nil {
+		value, label := item.Value, item.Label
+		link.Value = &value
+		link.Label = &label
 	}
 
 	return link
@@ -199,12 +356,22 @@ func (c *Client) NewWorkLogRowLink(colNumber int64, listID string, project *stri
 
 func (c *Client) NewWorkLogRequest() WorkLogRequest {
 
-	now := time.Now().Format(hrFlowDateFormat)
+	employments := c.Employments()
+	if c.EmploymentID != 0 {
+		employments = []Employment{}
+		for _, employment := range c.Employments() {
+			if employment.EmploymentID == c.EmploymentID {
+				employments = append(employments, employment)
+			}
+		}
+	}
+
+	now := c.now().Format(hrFlowDateFormat)
 	return WorkLogRequest{
 		ViewName:                          "employee",
 		Lang:                              "2",
 		IsUpdateRow:                       false,
-		Employments:                       c.Employments,
+		Employments:                       employments,
 		StartDate:                         now,
 		EndDate:                           now,
 		GetListsLabels:                    false,
@@ -220,67 +387,171 @@ func (c *Client) NewWorkLogRequest() WorkLogRequest {
 	}
 }
 
-type newWorkLogResponse struct {
+type workLogResponse struct {
 	ActionSuccessful bool `json:"actionSuccessful,omitempty"`
+	// Messages explain why the action wasn't successful.
+	Messages []string `json:"messages,omitempty"`
 }
 
-func (c *Client) NewWorkLog(startTime, endTime time.Time, salaryGroupValue string, comment string, project *string, lunch bool) error {
+// NewWorkLog reports a new row linked to project, a label starting with the value of the project, or to no project
+// if nil. The row is also copied to each of copyToDates with the same start and end times.
+func (c *Client) NewWorkLog(startTime, endTime time.Time, salaryGroupValue string, comment string, project *string, lunch bool, copyToDates ...time.Time) error {
+	return c.NewWorkLogContext(context.Background(), startTime, endTime, salaryGroupValue, comment, project, lunch, copyToDates...)
+}
 
-	if len(c.Employments) == 0 {
-		return errors.New("no employment found, cannot log hours")
-	}
+// NewWorkLogContext is like NewWorkLog but the request is bound to ctx.
+func (c *Client) NewWorkLogContext(ctx context.Context, startTime, endTime time.Time, salaryGroupValue string, comment string, project *string, lunch bool, copyToDates ...time.Time) error {
+	return c.NewWorkLogWithDimensionsContext(ctx, startTime, endTime, salaryGroupValue, comment, Dimensions{Project: projectItem(project)}, lunch, copyToDates...)
+}
 
-	employment := c.Employments[0]
+// NewWorkLogWithDimensions is like NewWorkLog but links the row to all of the dimensions.
+func (c *Client) NewWorkLogWithDimensions(startTime, endTime time.Time, salaryGroupValue string, comment string, dimensions Dimensions, lunch bool, copyToDates ...time.Time) error {
+	return c.NewWorkLogWithDimensionsContext(context.Background(), startTime, endTime, salaryGroupValue, comment, dimensions, lunch, copyToDates...)
+}
+
+// NewWorkLogWithDimensionsContext is like NewWorkLogWithDimensions but the request is bound to ctx.
+func (c *Client) NewWorkLogWithDimensionsContext(ctx context.Context, startTime, endTime time.Time, salaryGroupValue string, comment string, dimensions Dimensions, lunch bool, copyToDates ...time.Time) error {
+
+	employment, err := c.Employment(startTime)
+	if err != nil {
+		return errors.Wrap(err, "selecting employment, cannot log hours")
+	}
 
 	// Salary group is always the same, but probably shouldn't be hardcoded. No good way to get it right now.
-	row := c.NewWorkLogRow(employment.EmploymentID, employment.PersonID, employment.GroupID, startTime, endTime, salaryGroupValue, comment, project)
-	if !lunch {
-		row.LunchBreak = 0
-		row.CutLunchFromAmount = "N"
+	row := c.NewWorkLogRowWithDimensions(employment.EmploymentID, employment.PersonID, employment.GroupID, startTime, endTime, salaryGroupValue, comment, dimensions)
+	row.SetLunch(lunch)
+
+	return c.saveWorkLogRow(ctx, row, c.NewWorkLogRequest(), copyToDates)
+}
+
+// UpdateWorkLog saves the changes made to an existing row, usually one returned by WorkLogs.
+func (c *Client) UpdateWorkLog(row WorkLogRow) error {
+	return c.UpdateWorkLogContext(context.Background(), row)
+}
+
+// UpdateWorkLogContext is like UpdateWorkLog but the request is bound to ctx.
+func (c *Client) UpdateWorkLogContext(ctx context.Context, row WorkLogRow) error {
+
+	if row.Id == 0 {
+		return errors.New("row has no id, use NewWorkLog for new rows")
+	}
+	if row.Locked() {
+		return fmt.Errorf("row %d is already approved and can't be changed", row.Id)
 	}
+
+	workLogRequest := c.NewWorkLogRequest()
+	workLogRequest.IsUpdateRow = true
+	workLogRequest.StatusList = []string{row.Status}
+	workLogRequest.EmailChangesText = "Update"
+
+	// Updates are saved like new rows, the flag in the request tells the backend to replace the row.
+	return c.saveWorkLogRow(ctx, row, workLogRequest, nil)
+}
+
+// saveWorkLogRow posts the row and request and checks the backend response.
+func (c *Client) saveWorkLogRow(ctx context.Context, row WorkLogRow, workLogRequest WorkLogRequest, copyToDates []time.Time) error {
+
 	rowJSON, err := json.Marshal(row)
 	if err != nil {
 		return errors.Wrap(err, "marshaling work log row")
 	}
-	workLogRequest := c.NewWorkLogRequest()
 	workLogRequestJSON, err := json.Marshal(workLogRequest)
 	if err != nil {
 		return errors.Wrap(err, "marshaling work log request")
 	}
+	// Copy dates are midnights like the date of the row itself.
+	copyDates := []string{}
+	for _, date := range copyToDates {
+		copyDates = append(copyDates, time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location()).Format(hrFlowTimeFormat))
+	}
+	copyDatesJSON, err := json.Marshal(copyDates)
+	if err != nil {
+		return errors.Wrap(err, "marshaling copy dates")
+	}
 	body := url.Values{}
 	body.Add("workLogRow", string(rowJSON))
 	body.Add("workLogRequest", string(workLogRequestJSON))
 	// This has to be there
 	body.Add("action", `{"Action":"T","ActionId":1999,"Receiver":null,"Label":"Save","SelectedReceiver":null,"Comment":""}`)
-	body.Add("copyToDates", "[]")
+	body.Add("copyToDates", string(copyDatesJSON))
 
-	req, err := http.NewRequest("POST", "https://hrflow.accountor.fi/KirjaamoWeb/employee/NewWorkLogRow", strings.NewReader(body.Encode()))
-	if err != nil {
-		return errors.Wrap(err, "creating new work log request")
-	}
-	req.Header.Add("X-XSRF-TOKEN", c.xsrfToken)
-	req.Header.Add("Accept", "application/json")
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
-	resp, err := c.HttpClient.Do(req)
+	var response workLogResponse
+
+	err = c.postForm(ctx, c.endpoint(NewWorkLogRowPath), body, &response)
 	if err != nil {
 		return errors.Wrap(err, "work log request")
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("http status error %d %s", resp.StatusCode, resp.Status)
+	if !response.ActionSuccessful {
+		return &ValidationError{Messages: response.Messages}
+	}
+
+	return nil
+}
+
+// DeleteWorkLog removes an existing row. Approved rows can't be deleted.
+func (c *Client) DeleteWorkLog(row WorkLogRow) error {
+	return c.DeleteWorkLogContext(context.Background(), row)
+}
+
+// DeleteWorkLogContext is like DeleteWorkLog but the request is bound to ctx.
+func (c *Client) DeleteWorkLogContext(ctx context.Context, row WorkLogRow) error {
+
+	if row.Locked() {
+		return fmt.Errorf("row %d is already approved and can't be deleted", row.Id)
+	}
+
+	workLogRequest := c.NewWorkLogRequest()
+	workLogRequest.StatusList = []string{row.Status}
+	workLogRequest.EmailChangesText = "Delete"
+	workLogRequestJSON, err := json.Marshal(workLogRequest)
+	if err != nil {
+		return errors.Wrap(err, "marshaling work log request")
 	}
+	body := url.Values{}
+	body.Add("workLogRowId", strconv.FormatInt(row.Id, 10))
+	body.Add("workLogRequest", string(workLogRequestJSON))
 
-	var response newWorkLogResponse
+	var response workLogResponse
 
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	err = c.postForm(ctx, c.endpoint(DeleteWorkLogRowPath), body, &response)
 	if err != nil {
-		return errors.Wrap(err, "decoding new work log response")
+		return errors.Wrap(err, "delete work log request")
 	}
 
 	if !response.ActionSuccessful {
-		return errors.New("backend returned unsuccessful status")
+		return &ValidationError{Messages: response.Messages}
 	}
 
 	return nil
 }
+
+// WorkLogs returns all work log rows between startDate and endDate regardless of their status.
+func (c *Client) WorkLogs(startDate, endDate time.Time) ([]WorkLogRow, error) {
+	return c.WorkLogsContext(context.Background(), startDate, endDate)
+}
+
+// WorkLogsContext is like WorkLogs but the request is bound to ctx.
+func (c *Client) WorkLogsContext(ctx context.Context, startDate, endDate time.Time) ([]WorkLogRow, error) {
+
+	workLogRequest := c.NewWorkLogRequest()
+	workLogRequest.StartDate = startDate.Format(hrFlowDateFormat)
+	workLogRequest.EndDate = endDate.Format(hrFlowDateFormat)
+	workLogRequest.StatusList = allStatuses
+	workLogRequest.GetListsLabels = true
+	workLogRequestJSON, err := json.Marshal(workLogRequest)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshaling work log request")
+	}
+	body := url.Values{}
+	body.Add("workLogRequest", string(workLogRequestJSON))
+
+	var rows []WorkLogRow
+
+	err = c.postForm(ctx, c.endpoint(WorkLogRowsPath), body, &rows)
+	if err != nil {
+		return nil, errors.Wrap(err, "work logs request")
+	}
+
+	return rows, nil
+}
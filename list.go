@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func listCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "list",
+		Action: list,
+		Usage:  "list reported hours",
+		Flags: []cli.Flag{
+			&cli.TimestampFlag{
+				Name:        "start",
+				Aliases:     []string{"s"},
+				Layout:      dateLayout,
+				Usage:       "first `DATE` to list, format 'd.M.'",
+				DefaultText: "first day of the month",
+			},
+			&cli.TimestampFlag{
+				Name:        "end",
+				Aliases:     []string{"e"},
+				Layout:      dateLayout,
+				Usage:       "last `DATE` to list, format 'd.M.'",
+				DefaultText: "today",
+			},
+		},
+	}
+}
+
+func list(c *cli.Context) error {
+
+	now := time.Now()
+	start := dateFlag(c, "start", now.AddDate(0, 0, 1-now.Day()))
+	end := dateFlag(c, "end", now)
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	rows, err := client.WorkLogsContext(c.Context, start, end)
+	if err != nil {
+		return errors.Wrap(err, "getting work logs")
+	}
+
+	days, err := rowsByDay(rows)
+	if err != nil {
+		return err
+	}
+
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		dayRows, ok := days[date]
+		if !ok {
+			continue
+		}
+		fmt.Println(date.Weekday(), date.Format("02.01.2006"))
+		for _, row := range dayRows {
+			printRow(row)
+		}
+	}
+
+	return nil
+}
+
+// rowsByDay groups rows by their date, sorted by start time.
+func rowsByDay(rows []hrflow.WorkLogRow) (map[time.Time][]hrflow.WorkLogRow, error) {
+
+	days := map[time.Time][]hrflow.WorkLogRow{}
+	for _, row := range rows {
+		day, err := row.Day()
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing work log date")
+		}
+		days[day] = append(days[day], row)
+	}
+	for _, dayRows := range days {
+		sort.Slice(dayRows, func(i, j int) bool {
+			return dayRows[i].StartTime < dayRows[j].StartTime
+		})
+	}
+
+	return days, nil
+}
+
+func printRow(row hrflow.WorkLogRow) {
+
+	var times string
+	start, startErr := row.Start()
+	end, endErr := row.End()
+	if startErr == nil && endErr == nil {
+		times = fmt.Sprintf("%s-%s", start.Format("15:04"), end.Format("15:04"))
+	}
+
+	// The hours are shown like in the other commands, with the lunch break deducted.
+	fmt.Printf("  %-8d %-11s %6.2fh  %s  %s\n", row.Id, times, row.WorkedHours(), row.Project(), row.Comment())
+}
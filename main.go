@@ -1,9 +1,13 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
+	"github.com/myyra/hrflow/hrflow"
 	"github.com/urfave/cli/v2"
 )
 
@@ -12,17 +16,67 @@ func main() {
 	app := &cli.App{
 		Version: "v0.3.0",
 		Usage:   "A CLI for HR Flow",
-		Before:  checkConfig,
+		Before: func(c *cli.Context) error {
+			err := checkConfig(c)
+			if err != nil {
+				return err
+			}
+			return setupCassette(c)
+		},
+		After: saveCassette,
+		// Exit codes are handled after the app has returned, so the After hook runs even if a command exits with cli.Exit.
+		ExitErrHandler: func(c *cli.Context, err error) {},
+		Flags: []cli.Flag{
+			&cli.Int64Flag{
+				Name:        "employment",
+				Usage:       "report to the employment with `ID`, see the employments command.",
+				EnvVars:     []string{"HRFLOW_EMPLOYMENT"},
+				DefaultText: "config or the default employment",
+			},
+			&cli.StringFlag{
+				Name:  "record",
+				Usage: "record the requests to `FILE` with credentials, tokens and the username redacted, e.g. for reporting bugs.",
+			},
+			&cli.StringFlag{
+				Name:  "replay",
+				Usage: "respond to requests from a recording in `FILE` instead of HR Flow.",
+			},
+		},
 		Commands: []*cli.Command{
 			reportCommandFactory(),
 			calendarCommandFactory(),
+			listCommandFactory(),
+			editCommandFactory(),
+			deleteCommandFactory(),
+			employmentsCommandFactory(),
+			balanceCommandFactory(),
+			fillCommandFactory(),
+			missingCommandFactory(),
+			weekCommandFactory(),
+			dimensionCommandFactory("projects", "list projects reports can be assigned to", hrflow.ListProjects),
+			dimensionCommandFactory("departments", "list departments reports can be assigned to", hrflow.ListDepartments),
+			dimensionCommandFactory("cost-centers", "list cost centers reports can be assigned to", hrflow.ListCostCenters),
 		},
 		EnableBashCompletion: true,
 	}
 
-	err := app.Run(os.Args)
+	// Interrupting cancels the requests in progress instead of killing the process in the middle of them.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+		cancel()
+		signal.Stop(signals)
+	}()
+
+	err := app.RunContext(ctx, os.Args)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+		message, code := explain(err)
+		if len(message) != 0 {
+			fmt.Println(message)
+		}
+		os.Exit(code)
 	}
 }
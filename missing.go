@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli/v2"
+)
+
+func missingCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "missing",
+		Action: missing,
+		Usage:  "list past workdays of the month without reports, exits with 2 if there are any",
+		Flags: append(periodFlags(),
+			&cli.BoolFlag{
+				Name:    "quiet",
+				Aliases: []string{"q"},
+				Usage:   "don't print anything, only set the exit code",
+			},
+		),
+	}
+}
+
+func missing(c *cli.Context) error {
+
+	start, end, err := periodFlag(c, true)
+	if err != nil {
+		return err
+	}
+	// Today isn't missing until it has passed.
+	if yesterday := midnight(time.Now()).AddDate(0, 0, -1); end.After(yesterday) {
+		end = yesterday
+	}
+	if end.Before(start) {
+		return nil
+	}
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	days, err := missingWorkdays(c, client, start, end)
+	if err != nil {
+		return err
+	}
+	if len(days) == 0 {
+		return nil
+	}
+
+	if !c.Bool("quiet") {
+		for _, date := range days {
+			fmt.Println(date.Weekday(), date.Format("02.01.2006"))
+		}
+	}
+
+	return cli.Exit("", exitMissingDays)
+}
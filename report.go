@@ -2,8 +2,11 @@ package main
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/myyra/hrflow/hrflow"
 	"github.com/pkg/errors"
 	"github.com/urfave/cli/v2"
 )
@@ -36,7 +39,17 @@ func reportCommandFactory() *cli.Command {
 			&cli.StringFlag{
 				Name:        "project",
 				Aliases:     []string{"p"},
-				Usage:       "which `PROJECT` to assign to the report.",
+				Usage:       "which `PROJECT` to assign to the report, either its value or label from the projects command.",
+				DefaultText: "none",
+			},
+			&cli.StringFlag{
+				Name:        "department",
+				Usage:       "which `DEPARTMENT` to assign to the report, either its value or label from the departments command.",
+				DefaultText: "none",
+			},
+			&cli.StringFlag{
+				Name:        "cost-center",
+				Usage:       "which `COST_CENTER` to assign to the report, either its value or label from the cost-centers command.",
 				DefaultText: "none",
 			},
 			&cli.StringFlag{
@@ -51,6 +64,14 @@ func reportCommandFactory() *cli.Command {
 				Usage:       "`DATE` for the report, format 'd.M.' (years not supported)",
 				DefaultText: "today",
 			},
+			&cli.StringSliceFlag{
+				Name:  "dates",
+				Usage: "report the same entry to each of `DATES`, comma separated 'd.M.' dates. Can be repeated. Overrides --date.",
+			},
+			&cli.StringFlag{
+				Name:  "range",
+				Usage: "report the same entry to each workday in `RANGE`, formatted as 'd.M.-d.M.'. Overrides --date.",
+			},
 			&cli.BoolFlag{
 				Name:  "hourly",
 				Value: false,
@@ -81,6 +102,21 @@ func report(c *cli.Context) error {
 		date = &d
 	}
 
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+
+	dates, err := reportDates(c, client)
+	if err != nil {
+		return err
+	}
+	var copyToDates []time.Time
+	if len(dates) != 0 {
+		date = &dates[0]
+		copyToDates = dates[1:]
+	}
+
 	if end == nil {
 		t := time.Date(date.Year(), date.Month(), date.Day(), now.Hour(), now.Minute(), 0, 0, time.Local)
 		end = &t
@@ -98,38 +134,99 @@ func report(c *cli.Context) error {
 	}
 
 	hourly := c.Bool("hourly")
-	var salaryGroupValue string
-	if hourly {
-		salaryGroupValue = "11000"
-	} else {
-		salaryGroupValue = "99002"
-	}
+	salaryGroupValue := salaryGroup(hourly)
 
 	comment := c.String("comment")
-	p := c.String("project")
-	var project *string
-	if len(p) != 0 {
-		project = &p
+	dimensions, err := dimensionsFromFlags(c, client)
+	if err != nil {
+		return err
 	}
 
 	// Lunch is only applicable for monthly workers.
 	lunch := !hourly
 
-	client, err := clientFromConfig()
+	err = client.NewWorkLogWithDimensionsContext(c.Context, *start, *end, salaryGroupValue, comment, dimensions, lunch, copyToDates...)
 	if err != nil {
-		return errors.Wrap(err, "creating client from config")
+		return errors.Wrap(err, "creating work log")
 	}
-	err = client.Authenticate()
-	if err != nil {
-		return errors.Wrap(err, "authentication failed")
+
+	if len(copyToDates) != 0 {
+		fmt.Printf("report logged to %d dates\n", len(copyToDates)+1)
+	} else {
+		fmt.Println("report logged")
 	}
 
-	err = client.NewWorkLog(*start, *end, salaryGroupValue, comment, project, lunch)
-	if err != nil {
-		return errors.Wrap(err, "creating work log")
+	return nil
+}
+
+// reportDates returns the dates set with --dates or the workdays of --range, or nil if neither is set.
+func reportDates(c *cli.Context, client *hrflow.Client) ([]time.Time, error) {
+
+	var dates []time.Time
+
+	// The flag can be repeated, and each value can contain several dates.
+	for _, value := range c.StringSlice("dates") {
+		for _, d := range strings.Split(value, ",") {
+			d = strings.TrimSpace(d)
+			if len(d) == 0 {
+				continue
+			}
+			date, err := parseDate(d)
+			if err != nil {
+				return nil, errors.Wrapf(err, "parsing date %s", d)
+			}
+			dates = append(dates, date)
+		}
+	}
+
+	if r := c.String("range"); len(r) != 0 {
+		parts := strings.Split(r, "-")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid range %s, use format 'd.M.-d.M.'", r)
+		}
+		first, err := parseDate(parts[0])
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing range start")
+		}
+		last, err := parseDate(parts[1])
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing range end")
+		}
+		if last.Before(first) {
+			return nil, fmt.Errorf("range %s ends before it starts", r)
+		}
+		workCal, err := workCalendar(c, client, first, last)
+		if err != nil {
+			return nil, err
+		}
+		workdays := workCal.Workdays(first, last)
+		if len(workdays) == 0 {
+			return nil, fmt.Errorf("no workdays in range %s", r)
+		}
+		dates = append(dates, workdays...)
 	}
 
-	fmt.Println("report logged")
+	sort.Slice(dates, func(i, j int) bool {
+		return dates[i].Before(dates[j])
+	})
 
-	return nil
+	// A date given both in --dates and --range, or twice, would be reported twice.
+	unique := dates[:0]
+	for i, date := range dates {
+		if i == 0 || !date.Equal(dates[i-1]) {
+			unique = append(unique, date)
+		}
+	}
+
+	return unique, nil
+}
+
+// salaryGroup returns the salary group value of hourly or monthly workers.
+func salaryGroup(hourly bool) string {
+
+	if hourly {
+		return "11000"
+	}
+
+	return "99002"
 }
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+)
+
+// sessionPath returns the path of the file the session is stored in between runs.
+func sessionPath() (string, error) {
+
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "session.json"), nil
+}
+
+// restoreSession restores the stored session to client.
+func restoreSession(client *hrflow.Client) error {
+
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "opening session file")
+	}
+	defer file.Close()
+
+	var session hrflow.Session
+
+	err = json.NewDecoder(file).Decode(&session)
+	if err != nil {
+		return errors.Wrap(err, "decoding session")
+	}
+
+	return client.RestoreSession(session)
+}
+
+// saveSession stores the session of client to a file only readable by the user.
+func saveSession(client *hrflow.Client) error {
+
+	path, err := sessionPath()
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0700)
+	if err != nil {
+		return errors.Wrap(err, "creating session directory")
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return errors.Wrap(err, "creating session file")
+	}
+	defer file.Close()
+
+	err = json.NewEncoder(file).Encode(client.Session())
+	if err != nil {
+		return errors.Wrap(err, "encoding session")
+	}
+
+	return nil
+}
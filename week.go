@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/myyra/hrflow/hrflow"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+func weekCommandFactory() *cli.Command {
+
+	return &cli.Command{
+		Name:   "week",
+		Action: week,
+		Usage:  "print the reports of a week with the expected hours",
+		Flags: []cli.Flag{
+			&cli.IntFlag{
+				Name:        "week",
+				Aliases:     []string{"w"},
+				Usage:       "ISO `WEEK` number of the current year",
+				DefaultText: "current week",
+			},
+		},
+	}
+}
+
+func week(c *cli.Context) error {
+
+	start, end, err := periodFlag(c, false)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	client, err := authenticatedClient(c)
+	if err != nil {
+		return err
+	}
+	workCal, err := workCalendar(c, client, start, end)
+	if err != nil {
+		return err
+	}
+	rows, err := client.WorkLogsContext(c.Context, start, end)
+	if err != nil {
+		return errors.Wrap(err, "getting work logs")
+	}
+	days, err := rowsByDay(rows)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "DAY\tSTART\tEND\tLUNCH\tHOURS\tPROJECT\tCOMMENT")
+
+	var total float64
+	for date := start; !date.After(end); date = date.AddDate(0, 0, 1) {
+		label := date.Format("Mon 02.01.")
+		var dayTotal float64
+		for _, row := range days[date] {
+			var startTime, endTime string
+			if t, err := row.Start(); err == nil {
+				startTime = t.Format("15:04")
+			}
+			if t, err := row.End(); err == nil {
+				endTime = t.Format("15:04")
+			}
+			var lunch string
+			if row.CutLunchFromAmount == "Y" {
+				lunch = fmt.Sprintf("%dmin", row.LunchBreak)
+			}
+			// Rejected rows have to be reported again, so they don't count like in the balance.
+			comment := row.Comment()
+			if row.Status == hrflow.StatusRejected {
+				comment = strings.TrimSpace(hrflow.StatusRejected + " " + comment)
+			} else {
+				dayTotal += row.WorkedHours()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%.2f\t%s\t%s\n",
+				label, startTime, endTime, lunch, row.WorkedHours(), row.Project(), comment)
+			label = ""
+		}
+
+		// Days off are explained, e.g. holidays on weekdays.
+		var note string
+		if day, ok := workCal.Day(date); ok && !workCal.IsWorkday(date) {
+			note = strings.TrimSpace(day.Description)
+		}
+		fmt.Fprintf(w, "%s\t\t\t\t%.2f\t\t%s\n", label, dayTotal, note)
+		total += dayTotal
+	}
+
+	expected := workCal.ExpectedHours(start, end, cfg.dailyHours())
+	fmt.Fprintln(w, "\t\t\t\t\t\t")
+	fmt.Fprintf(w, "total\t\t\t\t%.2f\t\t\n", total)
+	fmt.Fprintf(w, "expected\t\t\t\t%.2f\t\t%d workdays\n", expected, workCal.WorkdaysBetween(start, end))
+	fmt.Fprintf(w, "difference\t\t\t\t%+.2f\t\t\n", total-expected)
+
+	return w.Flush()
+}